@@ -0,0 +1,130 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// AccessKey is an API key/secret pair of a sub-account.
+type AccessKey struct {
+	Name      string    `json:"name,omitempty"`
+	Key       string    `json:"api_key"`
+	Secret    string    `json:"api_secret"`
+	Enabled   bool      `json:"enabled"`
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+}
+
+// UnmarshalJSON accepts both the access keys API fields (api_key, api_secret)
+// and the shorter ones embedded in sub-account details (key, secret).
+func (k *AccessKey) UnmarshalJSON(b []byte) error {
+	type accessKey AccessKey
+	var v struct {
+		accessKey
+		ShortKey    string `json:"key"`
+		ShortSecret string `json:"secret"`
+	}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+
+	*k = AccessKey(v.accessKey)
+	if k.Key == "" {
+		k.Key = v.ShortKey
+	}
+	if k.Secret == "" {
+		k.Secret = v.ShortSecret
+	}
+	return nil
+}
+
+// AccessKeyParams are the parameters for creating or updating an access key.
+type AccessKeyParams struct {
+	Name    string `json:"name,omitempty"`
+	Enabled *bool  `json:"enabled,omitempty"`
+}
+
+// AccessKeysQuery pages and sorts the access keys listing.
+type AccessKeysQuery struct {
+	PageSize  int
+	Page      int
+	SortBy    string // api_key, created_at, name or enabled
+	SortOrder string // asc or desc
+}
+
+// AccessKeys lists the access keys of a sub-account, q may be nil.
+// The total number of keys is returned along with the requested page.
+func (a *Account) AccessKeys(subAccountID string, q *AccessKeysQuery) ([]AccessKey, int, error) {
+	query := url.Values{}
+	if q != nil {
+		if q.PageSize > 0 {
+			query.Set("page_size", strconv.Itoa(q.PageSize))
+		}
+		if q.Page > 0 {
+			query.Set("page", strconv.Itoa(q.Page))
+		}
+		if q.SortBy != "" {
+			query.Set("sort_by", q.SortBy)
+		}
+		if q.SortOrder != "" {
+			query.Set("sort_order", q.SortOrder)
+		}
+	}
+
+	var res struct {
+		AccessKeys []AccessKey `json:"access_keys"`
+		Total      int         `json:"total"`
+	}
+	if err := a.call(http.MethodGet, accessKeysPath(subAccountID), query, nil, &res); err != nil {
+		return nil, 0, err
+	}
+	return res.AccessKeys, res.Total, nil
+}
+
+// CreateAccessKey generates a new access key for a sub-account
+func (a *Account) CreateAccessKey(subAccountID string, p *AccessKeyParams) (*AccessKey, error) {
+	res := &AccessKey{}
+	if err := a.call(http.MethodPost, accessKeysPath(subAccountID), nil, p, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// UpdateAccessKey renames, enables or disables an access key of a sub-account
+func (a *Account) UpdateAccessKey(subAccountID, apiKey string, p *AccessKeyParams) (*AccessKey, error) {
+	res := &AccessKey{}
+	if err := a.call(http.MethodPut, accessKeysPath(subAccountID)+"/"+url.PathEscape(apiKey), nil, p, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// EnableAccessKey is a shortcut to enable an access key
+func (a *Account) EnableAccessKey(subAccountID, apiKey string) (*AccessKey, error) {
+	enabled := true
+	return a.UpdateAccessKey(subAccountID, apiKey, &AccessKeyParams{Enabled: &enabled})
+}
+
+// DisableAccessKey is a shortcut to disable an access key
+func (a *Account) DisableAccessKey(subAccountID, apiKey string) (*AccessKey, error) {
+	enabled := false
+	return a.UpdateAccessKey(subAccountID, apiKey, &AccessKeyParams{Enabled: &enabled})
+}
+
+// DeleteAccessKey deletes an access key of a sub-account
+func (a *Account) DeleteAccessKey(subAccountID, apiKey string) error {
+	return a.delete(accessKeysPath(subAccountID) + "/" + url.PathEscape(apiKey))
+}
+
+func accessKeysPath(subAccountID string) string {
+	return "sub_accounts/" + url.PathEscape(subAccountID) + "/access_keys"
+}
@@ -0,0 +1,507 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const adminAPIFmt = "%s/%s/%s" // :base/:cloud_name/:path
+
+// WithCompression explicitly enables or disables gzip compression of the
+// admin API responses, which can be megabytes for listings and searches.
+// Compressed responses are decompressed while being decoded, never buffered.
+func WithCompression(enabled bool) Option {
+	return func(s *Service) error {
+		s.compression = &enabled
+		return nil
+	}
+}
+
+// Resource is an asset as described by the admin API.
+type Resource struct {
+	AssetID      string                 `json:"asset_id,omitempty"`
+	PublicID     string                 `json:"public_id"`
+	Format       string                 `json:"format,omitempty"`
+	Version      uint                   `json:"version,omitempty"`
+	ResourceType string                 `json:"resource_type"`
+	Type         string                 `json:"type"`
+	CreatedAt    time.Time              `json:"created_at"`
+	Size         int                    `json:"bytes"` // In bytes
+	Width        int                    `json:"width,omitempty"`
+	Height       int                    `json:"height,omitempty"`
+	Pages        int                    `json:"pages,omitempty"` // PDFs and animated images, see PageCount
+	Folder       string                 `json:"folder,omitempty"`
+	AssetFolder  string                 `json:"asset_folder,omitempty"` // Dynamic folder mode
+	DisplayName  string                 `json:"display_name,omitempty"` // Dynamic folder mode
+	URL          string                 `json:"url,omitempty"`
+	SecureURL    string                 `json:"secure_url,omitempty"`
+	ETag         string                 `json:"etag,omitempty"` // MD5 of the content
+	Tags         []string               `json:"tags,omitempty"`
+	Context      Context                `json:"context,omitempty"`
+	Metadata     map[string]interface{} `json:"metadata,omitempty"`
+
+	// Derived versions, only listed by the resource details,
+	// see DerivedResources
+	Derived           []Derived `json:"derived,omitempty"`
+	DerivedNextCursor string    `json:"derived_next_cursor,omitempty"`
+
+	// Activity of the asset, only returned by the search where available,
+	// see UnusedAssets
+	LastUpdated *LastUpdated `json:"last_updated,omitempty"`
+	AccessedAt  time.Time    `json:"accessed_at"`
+}
+
+// LastUpdated holds the times of the last changes of a resource.
+type LastUpdated struct {
+	UpdatedAt         time.Time `json:"updated_at"` // Of any change
+	TagsUpdatedAt     time.Time `json:"tags_updated_at"`
+	ContextUpdatedAt  time.Time `json:"context_updated_at"`
+	MetadataUpdatedAt time.Time `json:"metadata_updated_at"`
+	PublicIDUpdatedAt time.Time `json:"public_id_updated_at"`
+}
+
+// Derived is a version of a resource generated by a transformation.
+type Derived struct {
+	ID             string `json:"id"`
+	Transformation string `json:"transformation"`
+	Format         string `json:"format"`
+	Size           int    `json:"bytes"` // In bytes
+	URL            string `json:"url,omitempty"`
+	SecureURL      string `json:"secure_url,omitempty"`
+}
+
+// Context holds the custom key-value pairs of a resource.
+type Context map[string]string
+
+// UnmarshalJSON accepts both the flat form of search results
+// and the {"custom": {...}} form of the resources endpoints.
+func (c *Context) UnmarshalJSON(b []byte) error {
+	var v map[string]json.RawMessage
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	if custom, ok := v["custom"]; ok && len(v) == 1 {
+		v = nil
+		if err := json.Unmarshal(custom, &v); err != nil {
+			return err
+		}
+	}
+
+	*c = make(Context, len(v))
+	for key, raw := range v {
+		var value string
+		if err := json.Unmarshal(raw, &value); err != nil {
+			// Not a string, keep the JSON value as is
+			value = string(raw)
+		}
+		(*c)[key] = value
+	}
+	return nil
+}
+
+// ResourceList is a page of resources.
+type ResourceList struct {
+	Resources  []Resource `json:"resources"`
+	NextCursor string     `json:"next_cursor,omitempty"`
+}
+
+// ResourcesQuery filters and pages the resources listing.
+type ResourcesQuery struct {
+	Type       string // Delivery type, e.g. upload or private
+	Prefix     string
+	MaxResults int
+	NextCursor string
+	Direction  string // asc or desc
+	Tags       bool   // Include tags
+	Context    bool   // Include context
+	Metadata   bool   // Include structured metadata
+}
+
+func (q *ResourcesQuery) values() url.Values {
+	v := url.Values{}
+	if q == nil {
+		return v
+	}
+	if q.Prefix != "" {
+		v.Set("prefix", q.Prefix)
+	}
+	if q.MaxResults > 0 {
+		v.Set("max_results", strconv.Itoa(q.MaxResults))
+	}
+	if q.NextCursor != "" {
+		v.Set("next_cursor", q.NextCursor)
+	}
+	if q.Direction != "" {
+		v.Set("direction", q.Direction)
+	}
+	if q.Tags {
+		v.Set("tags", "true")
+	}
+	if q.Context {
+		v.Set("context", "true")
+	}
+	if q.Metadata {
+		v.Set("metadata", "true")
+	}
+	return v
+}
+
+// Resources lists a page of resources, q may be nil
+func (s *Service) Resources(resourceType string, q *ResourcesQuery) (*ResourceList, error) {
+	return s.ResourcesContext(context.Background(), resourceType, q)
+}
+
+// ResourcesContext is Resources bound to ctx
+func (s *Service) ResourcesContext(ctx context.Context, resourceType string, q *ResourcesQuery) (*ResourceList, error) {
+	path := "resources/" + resourceType
+	if q != nil && q.Type != "" {
+		path += "/" + q.Type
+	}
+
+	r, err := s.newAdminRequest(http.MethodGet, path, q.values(), nil)
+	if err != nil {
+		return nil, err
+	}
+	r.ctx = ctx
+
+	res := &ResourceList{}
+	if err := s.call(r, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// ResourcesByTag lists a page of resources having the given tag, q may be nil
+func (s *Service) ResourcesByTag(resourceType, tag string, q *ResourcesQuery) (*ResourceList, error) {
+	res := &ResourceList{}
+	if err := s.admin(http.MethodGet, "resources/"+resourceType+"/tags/"+url.PathEscape(tag), q.values(), nil, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Resource returns the details of a single resource of the upload type
+func (s *Service) Resource(publicID, resourceType string) (*Resource, error) {
+	res := &Resource{}
+	if err := s.admin(http.MethodGet, "resources/"+resourceType+"/upload/"+escapePublicID(s.prefixed(publicID)), nil, nil, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// UpdateOptions are the changes applied by UpdateResource,
+// zero values are left unchanged.
+type UpdateOptions struct {
+	ResourceType     string // image (default), video or raw
+	Type             string // Delivery type, upload by default
+	Tags             []string
+	Context          map[string]string
+	Metadata         map[string]interface{} // Structured metadata by external ID
+	ModerationStatus string
+	// AssetFolder moves the resource and DisplayName renames it,
+	// on clouds in dynamic folder mode
+	AssetFolder string
+	DisplayName string
+	// Invalidate purges the CDN cached copies of the resource
+	Invalidate bool
+	// Extra parameters are sent verbatim
+	Extra map[string]string
+}
+
+// UpdateResource updates the tags, context and other attributes
+// of a resource, opts may be nil
+func (s *Service) UpdateResource(ctx context.Context, publicID string, opts *UpdateOptions) (*Resource, error) {
+	if err := ValidatePublicID(publicID); err != nil {
+		return nil, err
+	}
+	if opts == nil {
+		opts = &UpdateOptions{}
+	}
+	rt := (&UploadOptions{ResourceType: opts.ResourceType}).resourceType()
+	typ := opts.Type
+	if typ == "" {
+		typ = "upload"
+	}
+
+	body := map[string]string{}
+	if len(opts.Tags) > 0 {
+		body["tags"] = strings.Join(opts.Tags, ",")
+	}
+	if len(opts.Context) > 0 {
+		body["context"] = encodeContext(opts.Context)
+	}
+	if len(opts.Metadata) > 0 {
+		if err := s.validateMetadata(ctx, opts.Metadata, false); err != nil {
+			return nil, err
+		}
+		m, err := encodeMetadata(opts.Metadata)
+		if err != nil {
+			return nil, err
+		}
+		body["metadata"] = m
+	}
+	if opts.ModerationStatus != "" {
+		body["moderation_status"] = opts.ModerationStatus
+	}
+	if opts.AssetFolder != "" {
+		body["asset_folder"] = opts.AssetFolder
+	}
+	if opts.DisplayName != "" {
+		body["display_name"] = opts.DisplayName
+	}
+	if opts.Invalidate {
+		body["invalidate"] = "true"
+	}
+	for key, value := range opts.Extra {
+		body[key] = value
+	}
+
+	r, err := s.newAdminRequest(http.MethodPost, "resources/"+rt+"/"+typ+"/"+escapePublicID(s.prefixed(publicID)), nil, body)
+	if err != nil {
+		return nil, err
+	}
+	r.ctx = ctx
+	r.mutates = true
+
+	res := &Resource{}
+	if err := s.call(r, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// DeleteResult lists the resources deleted by a bulk deletion.
+type DeleteResult struct {
+	Deleted    map[string]string `json:"deleted"` // Status by public ID
+	Partial    bool              `json:"partial"` // More resources are left, call again
+	NextCursor string            `json:"next_cursor,omitempty"`
+	// DryRun is set when nothing was deleted because of WithDryRun,
+	// Deleted then lists the resources that would be
+	DryRun bool `json:"-"`
+	// KeepOriginal is set when only the derived versions of the resources
+	// of Deleted are removed, the originals being kept
+	KeepOriginal bool `json:"-"`
+}
+
+// maxDeletedPerCall is the number of resources a deletion by prefix or
+// of all the resources removes at once
+const maxDeletedPerCall = 1000
+
+// dryRunDeleted lists the resources a deletion would remove, by pages of
+// 500 up to limit of them, or all of them when limit is 0
+func (s *Service) dryRunDeleted(ctx context.Context, resourceType string, q ResourcesQuery, limit int) (*DeleteResult, error) {
+	res := &DeleteResult{Deleted: map[string]string{}, DryRun: true}
+	listed := 0
+	for {
+		q.MaxResults = 500
+		list, err := s.ResourcesContext(ctx, resourceType, &q)
+		if err != nil {
+			return nil, err
+		}
+		for _, resource := range list.Resources {
+			res.Deleted[resource.PublicID] = "deleted"
+		}
+		listed += len(list.Resources)
+		if list.NextCursor == "" || len(list.Resources) == 0 {
+			return res, nil
+		}
+		if limit > 0 && listed >= limit {
+			res.Partial = true
+			return res, nil
+		}
+		q.NextCursor = list.NextCursor
+	}
+}
+
+// DeleteResourcesByPrefix deletes up to 1000 resources of the upload type
+// whose public ID starts with prefix, under the folder prefix
+func (s *Service) DeleteResourcesByPrefix(prefix, resourceType string) (*DeleteResult, error) {
+	prefix = s.prefixedPrefix(prefix)
+	r, err := s.newAdminRequest(http.MethodDelete, "resources/"+resourceType+"/upload", url.Values{"prefix": {prefix}}, nil)
+	if err != nil {
+		return nil, err
+	}
+	r.mutates = true
+
+	if s.skip(r) {
+		return s.dryRunDeleted(context.Background(), resourceType, ResourcesQuery{Type: "upload", Prefix: prefix}, maxDeletedPerCall)
+	}
+
+	res := &DeleteResult{}
+	if err := s.call(r, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// maxDeleteIDs is the number of public IDs a single deletion accepts
+const maxDeleteIDs = 100
+
+// DeleteResources deletes up to 100 resources of the upload type by public
+// ID, under the folder prefix
+func (s *Service) DeleteResources(publicIDs []string, resourceType string) (*DeleteResult, error) {
+	return s.deleteResources(context.Background(), resourceType, "upload", publicIDs)
+}
+
+func (s *Service) deleteResources(ctx context.Context, resourceType, typ string, publicIDs []string) (*DeleteResult, error) {
+	if len(publicIDs) > maxDeleteIDs {
+		return nil, fmt.Errorf("at most %d public IDs can be deleted at once", maxDeleteIDs)
+	}
+	if s.folderPrefix != "" {
+		ids := make([]string, len(publicIDs))
+		for i, id := range publicIDs {
+			ids[i] = s.prefixed(id)
+		}
+		publicIDs = ids
+	}
+
+	r, err := s.newAdminRequest(http.MethodDelete, "resources/"+resourceType+"/"+typ, url.Values{"public_ids[]": publicIDs}, nil)
+	if err != nil {
+		return nil, err
+	}
+	r.ctx = ctx
+	r.mutates = true
+
+	if s.skip(r) {
+		res := &DeleteResult{Deleted: map[string]string{}, DryRun: true}
+		for _, id := range publicIDs {
+			res.Deleted[id] = "deleted"
+		}
+		return res, nil
+	}
+
+	res := &DeleteResult{}
+	if err := s.call(r, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// SearchQuery is a search API request, see
+// https://cloudinary.com/documentation/search_api
+type SearchQuery struct {
+	Expression string              `json:"expression,omitempty"`
+	SortBy     []map[string]string `json:"sort_by,omitempty"`
+	MaxResults int                 `json:"max_results,omitempty"`
+	NextCursor string              `json:"next_cursor,omitempty"`
+	WithField  []string            `json:"with_field,omitempty"`
+	Aggregate  []string            `json:"aggregate,omitempty"`
+}
+
+// SearchResult is a page of search results.
+type SearchResult struct {
+	TotalCount   int                       `json:"total_count"`
+	Time         int                       `json:"time"`
+	Resources    []Resource                `json:"resources"`
+	NextCursor   string                    `json:"next_cursor,omitempty"`
+	Aggregations map[string]map[string]int `json:"aggregations,omitempty"`
+}
+
+// Search finds resources matching the query
+func (s *Service) Search(q *SearchQuery) (*SearchResult, error) {
+	return s.SearchContext(context.Background(), q)
+}
+
+// SearchContext is Search bound to ctx
+func (s *Service) SearchContext(ctx context.Context, q *SearchQuery) (*SearchResult, error) {
+	r, err := s.newAdminRequest(http.MethodPost, "resources/search", nil, q)
+	if err != nil {
+		return nil, err
+	}
+	r.ctx = ctx
+
+	res := &SearchResult{}
+	if err := s.call(r, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// escapePublicID escapes a public ID for a URL path, keeping its folders
+func escapePublicID(publicID string) string {
+	parts := strings.Split(publicID, "/")
+	for i, part := range parts {
+		parts[i] = url.PathEscape(part)
+	}
+	return strings.Join(parts, "/")
+}
+
+// admin calls the admin API, body is sent as JSON when not nil
+func (s *Service) admin(method, path string, query url.Values, body, v interface{}) error {
+	r, err := s.newAdminRequest(method, path, query, body)
+	if err != nil {
+		return err
+	}
+	return s.call(r, v)
+}
+
+func (s *Service) newAdminRequest(method, path string, query url.Values, body interface{}) (*request, error) {
+	r, err := s.newRequest(fmt.Sprintf(adminAPIFmt, s.apiURL, s.cloudName, path), method, nil)
+	if err != nil {
+		return nil, err
+	}
+	r.admin = true
+	r.family = AdminAPI
+	r.query = query
+
+	if body != nil {
+		if r.json, err = json.Marshal(body); err != nil {
+			return nil, err
+		}
+	}
+
+	if s.compression != nil {
+		if *s.compression {
+			r.header.Set("Accept-Encoding", "gzip")
+		} else {
+			r.header.Set("Accept-Encoding", "identity")
+		}
+	}
+
+	return r, nil
+}
+
+func (r *request) buildAdmin(apiKey, apiSecret string) (*http.Request, func() error, error) {
+	uri := r.uri
+	if len(r.query) > 0 {
+		uri += "?" + r.query.Encode()
+	}
+
+	var req *http.Request
+	var err error
+	if r.json != nil {
+		req, err = http.NewRequestWithContext(r.ctx, r.method, uri, bytes.NewReader(r.json))
+	} else {
+		req, err = http.NewRequestWithContext(r.ctx, r.method, uri, nil)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for key, values := range r.header {
+		req.Header[key] = values
+	}
+	if r.json != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	// Left to the bearer token otherwise
+	if apiSecret != "" {
+		req.SetBasicAuth(apiKey, apiSecret)
+	}
+
+	return req, func() error { return nil }, nil
+}
@@ -0,0 +1,90 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+)
+
+// TransformationUsage is how much a named transformation is used.
+type TransformationUsage struct {
+	Name    string
+	Derived int // Derived resources generated by it
+}
+
+// TransformationReport ranks the named transformations of the account,
+// e.g. to decide on the strict allowlist or on a cleanup.
+type TransformationReport struct {
+	MostUsed  []TransformationUsage // Used ones, most derived resources first
+	NeverUsed []string
+}
+
+// TransformationReport counts the derived resources of every used named
+// transformation, it takes a request per page of 500 derived resources
+func (s *Service) TransformationReport(ctx context.Context) (*TransformationReport, error) {
+	report := &TransformationReport{}
+	var cursor string
+	for {
+		list, err := s.Transformations(ctx, cursor)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range list.Transformations {
+			if !t.Named {
+				continue
+			}
+			if !t.Used {
+				report.NeverUsed = append(report.NeverUsed, t.Name)
+				continue
+			}
+			n, err := s.derivedCount(ctx, t.Name)
+			if err != nil {
+				return nil, fmt.Errorf("transformation %s: %w", t.Name, err)
+			}
+			report.MostUsed = append(report.MostUsed, TransformationUsage{Name: t.Name, Derived: n})
+		}
+		if cursor = list.NextCursor; cursor == "" {
+			break
+		}
+	}
+
+	sort.SliceStable(report.MostUsed, func(i, j int) bool {
+		return report.MostUsed[i].Derived > report.MostUsed[j].Derived
+	})
+	sort.Strings(report.NeverUsed)
+	return report, nil
+}
+
+// derivedCount counts the derived resources of a transformation
+func (s *Service) derivedCount(ctx context.Context, name string) (int, error) {
+	query := url.Values{"transformation": {name}, "max_results": {"500"}}
+	var n int
+	for {
+		r, err := s.newAdminRequest(http.MethodGet, "transformations", query, nil)
+		if err != nil {
+			return 0, err
+		}
+		r.ctx = ctx
+
+		var res struct {
+			Derived    []struct{} `json:"derived"`
+			NextCursor string     `json:"next_cursor"`
+		}
+		if err := s.call(r, &res); err != nil {
+			return 0, err
+		}
+		n += len(res.Derived)
+		if res.NextCursor == "" {
+			return n, nil
+		}
+		query.Set("next_cursor", res.NextCursor)
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import (
+	"strconv"
+	"strings"
+)
+
+// AnimatedOptions shape the delivery of an animated image, e.g. a GIF,
+// zero values leave the defaults of Cloudinary.
+type AnimatedOptions struct {
+	// Format converts the animation, e.g. webp, avif or mp4
+	Format string
+	// Delay between the frames, in milliseconds
+	Delay int
+	// Frame selects a single frame, from 1, instead of the animation
+	Frame          int
+	Transformation string // Applied first, e.g. c_scale,w_480
+}
+
+// AnimatedTransformation returns the transformation keeping an image
+// animated in the given format, or extracting a single frame
+func AnimatedTransformation(a *AnimatedOptions) string {
+	if a == nil {
+		a = &AnimatedOptions{}
+	}
+
+	var params []string
+	if a.Frame > 0 {
+		params = append(params, "pg_"+strconv.Itoa(a.Frame))
+	} else {
+		if a.Delay > 0 {
+			params = append(params, "dl_"+strconv.Itoa(a.Delay))
+		}
+		switch strings.ToLower(a.Format) {
+		case "webp":
+			params = append(params, "fl_animated.awebp")
+		case "mp4", "webm":
+			// Videos are animated anyway
+		default:
+			params = append(params, "fl_animated")
+		}
+	}
+
+	t := strings.Join(params, ",")
+	if a.Transformation != "" {
+		if t == "" {
+			return strings.Trim(a.Transformation, "/")
+		}
+		return strings.Trim(a.Transformation, "/") + "/" + t
+	}
+	return t
+}
+
+// AnimatedURL returns the URL of an animated image, see AnimatedTransformation
+func (s *Service) AnimatedURL(publicID string, a *AnimatedOptions) string {
+	opts := &URLOptions{Transformation: AnimatedTransformation(a)}
+	if a != nil {
+		opts.Format = a.Format
+	}
+	return s.URL(publicID, opts)
+}
+
+// AnimatedWebPURL returns the URL of an animated image converted to an
+// animated WebP, usually much lighter than a GIF
+func (s *Service) AnimatedWebPURL(publicID string) string {
+	return s.AnimatedURL(publicID, &AnimatedOptions{Format: "webp"})
+}
+
+// AnimatedAVIFURL returns the URL of an animated image converted to an
+// animated AVIF
+func (s *Service) AnimatedAVIFURL(publicID string) string {
+	return s.AnimatedURL(publicID, &AnimatedOptions{Format: "avif"})
+}
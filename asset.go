@@ -0,0 +1,95 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import "context"
+
+// Asset is a handle on an uploaded resource, for chaining calls.
+type Asset struct {
+	PublicID     string
+	ResourceType string
+	Type         string // Delivery type
+	Version      uint
+	Format       string
+
+	s *Service
+}
+
+// Asset returns the handle of an uploaded resource
+func (s *Service) Asset(resp *Response) *Asset {
+	rt := resp.ResourceType
+	if rt == "" {
+		rt = "image"
+	}
+	return &Asset{
+		PublicID:     resp.PublicID,
+		ResourceType: rt,
+		Type:         "upload",
+		Version:      resp.Version,
+		Format:       resp.Format,
+		s:            s,
+	}
+}
+
+// UploadAsset uploads as Upload does and returns the handle of the resource
+func (s *Service) UploadAsset(ctx context.Context, file interface{}, opts *UploadOptions) (*Asset, error) {
+	resp, err := s.Upload(ctx, file, opts)
+	if err != nil {
+		return nil, err
+	}
+	a := s.Asset(resp)
+	if o := s.uploadOptions(opts); o != nil && o.Type != "" {
+		a.Type = o.Type
+	}
+	return a, nil
+}
+
+// URL returns the delivery URL of the asset, at its version and format
+// unless set in opts, which may be nil
+func (a *Asset) URL(opts *URLOptions) string {
+	o := URLOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	o.ResourceType, o.Type = a.ResourceType, a.Type
+	if o.Version == 0 {
+		o.Version = a.Version
+	}
+	if o.Format == "" && a.ResourceType != "raw" {
+		o.Format = a.Format
+	}
+	return a.s.URL(a.PublicID, &o)
+}
+
+// Destroy deletes the asset
+func (a *Asset) Destroy(ctx context.Context) error {
+	return a.s.Destroy(ctx, a.PublicID, &DestroyOptions{ResourceType: a.ResourceType, Type: a.Type})
+}
+
+// AddTags adds the tags to the asset
+func (a *Asset) AddTags(ctx context.Context, tags ...string) error {
+	return a.s.AddTags(ctx, a.PublicID, &TagOptions{ResourceType: a.ResourceType, Type: a.Type}, tags...)
+}
+
+// Explicit applies the options to the asset, see Service.Explicit,
+// opts may be nil. The handle follows the new version.
+func (a *Asset) Explicit(ctx context.Context, opts *UploadOptions) (*Response, error) {
+	o := UploadOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	o.ResourceType, o.Type = a.ResourceType, a.Type
+
+	resp, err := a.s.Explicit(ctx, a.PublicID, &o)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Version != 0 {
+		a.Version = resp.Version
+	}
+	return resp, nil
+}
@@ -0,0 +1,108 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// The auto resource type lets Cloudinary pick image, video or raw on upload.
+// The picked type is remembered by public ID so Destroy and URL can be given
+// auto too.
+
+// Assets remembered by default, see WithAssetMemory
+const defaultAssetMemory = 10000
+
+// WithAssetMemory bounds the types of the auto uploads and the versions
+// remembered to the n most recently used assets each, 10000 by default.
+// Forgotten types are asked to the Admin API again and forgotten versions
+// are left out of the URLs, 0 remembers none.
+func WithAssetMemory(n int) Option {
+	return func(s *Service) error {
+		if n < 0 {
+			return errors.New("negative asset memory")
+		}
+		s.ownState()
+		s.state.resourceTypes, s.state.versions = newLRU(n), newLRU(n)
+		return nil
+	}
+}
+
+// rememberType wraps an upload of the auto type to remember its picked type
+func (s *Service) rememberType(upload func() (*Response, error)) func() (*Response, error) {
+	return func() (*Response, error) {
+		resp, err := upload()
+		if err == nil && resp != nil && resp.PublicID != "" && resp.ResourceType != "" {
+			s.state.resourceTypes.Store(resp.PublicID, resp.ResourceType)
+		}
+		return resp, err
+	}
+}
+
+// resolveType returns the resource type of an asset uploaded as auto,
+// asking the Admin API when it was not uploaded through the service
+func (s *Service) resolveType(ctx context.Context, publicID string) (string, error) {
+	if rt, ok := s.state.resourceTypes.Load(publicID); ok {
+		return rt.(string), nil
+	}
+	res, err := s.findResource(ctx, publicID)
+	if err != nil {
+		return "", err
+	}
+	return res.ResourceType, nil
+}
+
+// findResource returns an asset of the upload type from the Admin API,
+// trying its remembered resource type or else each one in turn
+func (s *Service) findResource(ctx context.Context, publicID string) (*Resource, error) {
+	types := []string{"image", "video", "raw"}
+	if rt, ok := s.state.resourceTypes.Load(publicID); ok {
+		types = []string{rt.(string)}
+	}
+
+	for _, rt := range types {
+		r, err := s.newAdminRequest(http.MethodGet, "resources/"+rt+"/upload/"+escapePublicID(publicID), nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		r.ctx = ctx
+
+		res := &Resource{}
+		err = s.call(r, res)
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if res.ResourceType == "" {
+			res.ResourceType = rt
+		}
+		s.state.resourceTypes.Store(publicID, rt)
+		return res, nil
+	}
+	return nil, fmt.Errorf("resource %s not found", publicID)
+}
+
+// deliveryType returns the resource type to deliver an asset with,
+// image when auto is not resolved
+func (s *Service) deliveryType(publicID, rt string) string {
+	if rt == "auto" {
+		if picked, ok := s.state.resourceTypes.Load(publicID); ok {
+			return picked.(string)
+		}
+		return "image"
+	}
+	if rt == "" {
+		return "image"
+	}
+	return rt
+}
@@ -0,0 +1,59 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// BackupVersion is a backed up version of an asset.
+type BackupVersion struct {
+	VersionID  string    `json:"version_id"`
+	Version    string    `json:"version"`
+	Format     string    `json:"format"`
+	Size       int       `json:"size"` // In bytes
+	Time       time.Time `json:"time"`
+	Restorable bool      `json:"restorable"`
+}
+
+// BackupVersions lists the backed up versions of a resource of the upload
+// type along with its asset ID, needed to download them
+func (s *Service) BackupVersions(ctx context.Context, publicID, resourceType string) (string, []BackupVersion, error) {
+	r, err := s.newAdminRequest(http.MethodGet, "resources/"+resourceType+"/upload/"+escapePublicID(s.prefixed(publicID)), url.Values{"versions": {"true"}}, nil)
+	if err != nil {
+		return "", nil, err
+	}
+	r.ctx = ctx
+
+	var res struct {
+		AssetID  string          `json:"asset_id"`
+		Versions []BackupVersion `json:"versions"`
+	}
+	if err := s.call(r, &res); err != nil {
+		return "", nil, err
+	}
+	return res.AssetID, res.Versions, nil
+}
+
+// BackupDownloadURL returns the signed URL downloading a backed up version
+// of an asset, the URL expires along with its signature timestamp
+func (s *Service) BackupDownloadURL(assetID, versionID string) string {
+	params := s.signParams(map[string]string{
+		"asset_id":   assetID,
+		"version_id": versionID,
+	})
+
+	query := url.Values{}
+	for key, value := range params {
+		query.Set(key, value)
+	}
+	return fmt.Sprintf("%s/%s/download_backup?%s", s.apiURL, s.cloudName, query.Encode())
+}
@@ -0,0 +1,176 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrBudgetExceeded is returned without calling the API for the requests
+// a BudgetGuard rejects.
+var ErrBudgetExceeded = errors.New("usage budget exceeded")
+
+// Budget is the usage allowed in a month, a zero limit is not enforced.
+type Budget struct {
+	Transformations float64
+	Bandwidth       float64 // In bytes
+	Credits         float64
+}
+
+// BudgetRequest describes a request for the policy of a BudgetGuard.
+type BudgetRequest struct {
+	Family APIFamily
+	Method string
+	// Path follows the cloud name, e.g. image/upload or resources/image
+	Path string
+}
+
+// EssentialRequest is the default policy of the BudgetGuard: every request
+// is essential but those of the Upload API creating assets or derived ones
+func EssentialRequest(r BudgetRequest) bool {
+	if r.Family != UploadAPI {
+		return true
+	}
+	action := r.Path[strings.LastIndex(r.Path, "/")+1:]
+	switch action {
+	case "upload", "explicit", "sprite", "multi", "explode", "text", "create_slideshow":
+		return false
+	}
+	return true
+}
+
+// BudgetGuard rejects the non-essential requests with ErrBudgetExceeded
+// once the usage of the month exceeds a budget. The usage is that of the
+// last Poll of the usage report, plus the eager transformations of the
+// responses received since. It is safe for concurrent use and may be
+// shared by several services of the same cloud.
+type BudgetGuard struct {
+	mu        sync.Mutex
+	budget    Budget
+	essential func(BudgetRequest) bool
+	now       func() time.Time
+
+	usage Budget    // As last polled
+	eager float64   // Transformations counted since
+	month time.Time // Of the usage
+}
+
+// NewBudgetGuard enforces budget, essential telling which requests go on
+// once exceeded, EssentialRequest if nil
+func NewBudgetGuard(budget Budget, essential func(BudgetRequest) bool) *BudgetGuard {
+	if essential == nil {
+		essential = EssentialRequest
+	}
+	return &BudgetGuard{budget: budget, essential: essential, now: time.Now}
+}
+
+// WithBudgetGuard guards every request of the Service with g, see Poll.
+func WithBudgetGuard(g *BudgetGuard) Option {
+	return func(s *Service) error {
+		if g == nil {
+			return errors.New("nil budget guard")
+		}
+		s.budget = g
+		return nil
+	}
+}
+
+// Poll updates the usage from the usage report of s
+func (g *BudgetGuard) Poll(ctx context.Context, s *Service) error {
+	u, err := s.usage(ctx, time.Time{})
+	if err != nil {
+		return err
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.usage = Budget{
+		Transformations: u.Transformations.Usage,
+		Bandwidth:       u.Bandwidth.Usage,
+		Credits:         u.Credits.Usage,
+	}
+	g.eager = 0
+	g.month = monthOf(g.now())
+	return nil
+}
+
+// Run polls the usage report of s every interval until ctx is done,
+// the polling errors are left out as the last usage is kept
+func (g *BudgetGuard) Run(ctx context.Context, s *Service, interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		g.Poll(ctx, s)
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// Exceeded reports whether the usage of the month exceeds the budget
+func (g *BudgetGuard) Exceeded() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.exceeded()
+}
+
+func (g *BudgetGuard) exceeded() bool {
+	if !g.month.Equal(monthOf(g.now())) {
+		// Not polled yet this month
+		return false
+	}
+	over := func(usage, limit float64) bool {
+		return limit > 0 && usage >= limit
+	}
+	return over(g.usage.Transformations+g.eager, g.budget.Transformations) ||
+		over(g.usage.Bandwidth, g.budget.Bandwidth) ||
+		over(g.usage.Credits, g.budget.Credits)
+}
+
+func (g *BudgetGuard) allow(s *Service, r *request) error {
+	g.mu.Lock()
+	exceeded := g.exceeded()
+	g.mu.Unlock()
+	if !exceeded {
+		return nil
+	}
+
+	br := BudgetRequest{Family: r.family, Method: r.method}
+	if u, err := url.Parse(r.uri); err == nil {
+		br.Path = u.Path
+		if i := strings.Index(br.Path, "/"+s.cloudName+"/"); i >= 0 {
+			br.Path = br.Path[i+len(s.cloudName)+2:]
+		}
+	}
+	if g.essential(br) {
+		return nil
+	}
+	return ErrBudgetExceeded
+}
+
+// observe counts the eager transformations of a response
+func (g *BudgetGuard) observe(resp *Response) {
+	if len(resp.Eager) == 0 {
+		return
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.eager += float64(len(resp.Eager))
+}
+
+// monthOf returns the first instant of the month of t, in UTC
+func monthOf(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,174 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// CacheEntry is a cached Admin API response.
+type CacheEntry struct {
+	Body []byte
+	// ETag of the response, if any, expired entries having one are
+	// revalidated with a conditional request
+	ETag    string
+	Expires time.Time
+}
+
+// Cache stores the responses of the Admin API reads, e.g. resource details,
+// listings and usage. Implementations must be safe for concurrent use.
+type Cache interface {
+	// Get returns the entry of the key, if any, expired or not
+	Get(key string) (*CacheEntry, bool)
+	Set(key string, e *CacheEntry)
+	// Clear removes all the entries
+	Clear()
+}
+
+// WithCache caches the Admin API reads for ttl, reducing the rate limit
+// consumption. Once expired, the responses having an ETag are revalidated,
+// a Not Modified answer being used as a cache hit. The whole cache is
+// cleared by any successful mutation, e.g. an upload, a deletion or a tag
+// change, made through the service. Searches leave it as is.
+func WithCache(c Cache, ttl time.Duration) Option {
+	return func(s *Service) error {
+		if c == nil || ttl <= 0 {
+			return errors.New("cache requires a positive ttl")
+		}
+		s.cache, s.cacheTTL = c, ttl
+		return nil
+	}
+}
+
+// cacheKey returns the key of a cacheable request, empty otherwise
+func (r *request) cacheKey() string {
+	if !r.admin || r.method != http.MethodGet {
+		return ""
+	}
+	return r.uri + "?" + r.query.Encode()
+}
+
+// cached returns the cached entry of the request, if any, and whether it
+// is fresh. If not, the request is made conditional on its ETag.
+func (s *Service) cached(r *request) (*CacheEntry, bool) {
+	key := r.cacheKey()
+	if s.cache == nil || key == "" {
+		return nil, false
+	}
+	e, ok := s.cache.Get(key)
+	if !ok {
+		return nil, false
+	}
+	if s.now().Before(e.Expires) {
+		return e, true
+	}
+	if e.ETag == "" {
+		return nil, false
+	}
+
+	if r.header == nil {
+		r.header = http.Header{}
+	}
+	r.header.Set("If-None-Match", e.ETag)
+	return e, false
+}
+
+// revalidated renews the entry the API answered Not Modified to
+func (s *Service) revalidated(r *request, e *CacheEntry) io.ReadCloser {
+	renewed := *e
+	renewed.Expires = s.now().Add(s.cacheTTL)
+	s.cache.Set(r.cacheKey(), &renewed)
+	return ioutil.NopCloser(bytes.NewReader(e.Body))
+}
+
+// store caches the response body of a read, or clears the cache on
+// mutations. Uncached reads, e.g. searches, leave the cache as is.
+func (s *Service) store(r *request, header http.Header, body io.ReadCloser) (io.ReadCloser, error) {
+	if s.cache == nil {
+		return body, nil
+	}
+	key := r.cacheKey()
+	if key == "" {
+		if r.mutates {
+			s.cache.Clear()
+		}
+		return body, nil
+	}
+
+	defer body.Close()
+	b, err := ioutil.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+	s.cache.Set(key, &CacheEntry{Body: b, ETag: header.Get("ETag"), Expires: s.now().Add(s.cacheTTL)})
+	return ioutil.NopCloser(bytes.NewReader(b)), nil
+}
+
+// MemoryCache is an in-memory Cache dropping the expired entries, unless
+// they have an ETag, in which case they are kept for revalidation.
+type MemoryCache struct {
+	// KeepStale is how long the expired entries with an ETag are kept
+	KeepStale time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*CacheEntry
+	now     func() time.Time
+}
+
+// NewMemoryCache returns an empty in-memory cache keeping the stale
+// entries for an hour
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{KeepStale: time.Hour, entries: map[string]*CacheEntry{}, now: time.Now}
+}
+
+// Get returns the entry unless dropped
+func (c *MemoryCache) Get(key string) (*CacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if ok && c.dropped(e, c.now()) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return e, ok
+}
+
+// Set stores the entry, sweeping the dropped ones
+func (c *MemoryCache) Set(key string, e *CacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	for k, old := range c.entries {
+		if c.dropped(old, now) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = e
+}
+
+// Clear removes all the entries
+func (c *MemoryCache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = map[string]*CacheEntry{}
+}
+
+func (c *MemoryCache) dropped(e *CacheEntry, now time.Time) bool {
+	if e.ETag != "" {
+		return !now.Before(e.Expires.Add(c.KeepStale))
+	}
+	return !now.Before(e.Expires)
+}
@@ -0,0 +1,320 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"io/ioutil"
+	"sync"
+	"time"
+)
+
+const (
+	defaultChunkSize = 20 << 20
+	minChunkSize     = 5 << 20
+)
+
+// WithChunkSize sets the size of the chunks sent by UploadLarge,
+// Cloudinary requires at least 5MB.
+func WithChunkSize(size int) Option {
+	return func(s *Service) error {
+		if size < minChunkSize {
+			return errors.New("chunk size must be at least 5MB")
+		}
+		s.chunkSize = size
+		return nil
+	}
+}
+
+// WithChunkConcurrency sends up to n chunks at once when the file given to
+// UploadLarge is an io.ReaderAt of a known size, e.g. an *os.File.
+// The last chunk is always sent once the others are done.
+func WithChunkConcurrency(n int) Option {
+	return func(s *Service) error {
+		if n < 1 {
+			return errors.New("chunk concurrency must be positive")
+		}
+		s.chunkConcurrency = n
+		return nil
+	}
+}
+
+// UploadLarge uploads a file to cloudinary from a reader in several chunks,
+// as required for files larger than 100MB. Only a single chunk is held in
+// memory at a time, the total size is only sent along the last chunk.
+func (s *Service) UploadLarge(reader io.Reader, resourceType string) (*Response, error) {
+	opts := &UploadOptions{ResourceType: resourceType}
+	return s.track(context.Background(), opts, func() (*Response, error) {
+		return s.uploadLarge(context.Background(), reader, opts)
+	})
+}
+
+func (s *Service) uploadLarge(ctx context.Context, reader io.Reader, opts *UploadOptions) (*Response, error) {
+	id := uploadID(opts.idempotencyKey())
+	if opts.idempotencyKey() == "" {
+		var err error
+		if id, err = randomID(); err != nil {
+			return nil, err
+		}
+	}
+
+	size := s.chunkSize
+	if size == 0 {
+		size = defaultChunkSize
+	}
+
+	p, err := s.chunkProgress(opts.idempotencyKey(), id)
+	if err != nil {
+		return nil, err
+	}
+	id = p.state.ID
+
+	if s.chunkConcurrency > 1 {
+		if ra, start, end, ok := readerAtRange(reader); ok {
+			return s.uploadChunksAt(ctx, ra, start, end, int64(size), p, opts)
+		}
+	}
+
+	// Resuming, skip what was acknowledged
+	offset := p.state.Sent
+	if offset > 0 {
+		if seeker, ok := reader.(io.Seeker); ok {
+			_, err = seeker.Seek(offset, io.SeekCurrent)
+		} else {
+			_, err = io.CopyN(ioutil.Discard, reader, offset)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	br := bufio.NewReader(reader)
+	chunk := make([]byte, size)
+	for {
+		n, err := io.ReadFull(br, chunk)
+		if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+			return nil, err
+		}
+
+		last := err != nil
+		if !last {
+			if _, err := br.Peek(1); err == io.EOF {
+				last = true
+			} else if err != nil {
+				return nil, err
+			}
+		}
+
+		if n == 0 && offset == 0 {
+			return nil, errors.New("empty file")
+		}
+
+		total := "-1"
+		if last {
+			total = fmt.Sprint(offset + int64(n))
+		}
+
+		resp, err := s.uploadChunk(ctx, opts, id, bytesBody(chunk[:n]), offset, int64(n), total)
+		if err != nil {
+			return nil, err
+		}
+		if last {
+			return resp, p.finish()
+		}
+		if err := p.ack(offset, int64(n)); err != nil {
+			return nil, err
+		}
+		offset += int64(n)
+	}
+}
+
+// uploadChunksAt sends the [start, end) range of ra chunk by chunk,
+// all but the last one concurrently
+func (s *Service) uploadChunksAt(ctx context.Context, ra io.ReaderAt, start, end, size int64, p *chunkProgress, opts *UploadOptions) (*Response, error) {
+	if end <= start {
+		return nil, errors.New("empty file")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	sem := make(chan struct{}, s.chunkConcurrency)
+	id := p.state.ID
+	offset := start + p.state.Sent
+	for ; end-offset > size; offset += size {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(offset int64) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			_, err := s.uploadChunk(ctx, opts, id, sectionBody(ra, offset, size), offset-start, size, "-1")
+			if err == nil {
+				err = p.ack(offset-start, size)
+			}
+			if err != nil {
+				once.Do(func() {
+					firstErr = err
+					cancel()
+				})
+			}
+		}(offset)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	resp, err := s.uploadChunk(ctx, opts, id, sectionBody(ra, offset, end-offset), offset-start, end-offset, fmt.Sprint(end-start))
+	if err != nil {
+		return nil, err
+	}
+	return resp, p.finish()
+}
+
+// chunkProgress tracks the acknowledged chunks of an upload, saving them
+// to the store when there is one
+type chunkProgress struct {
+	store UploadStateStore
+	key   string
+
+	mu    sync.Mutex
+	state *UploadState
+	acked map[int64]int64 // Sizes of the chunks acknowledged out of order
+}
+
+// chunkProgress loads the progress of the upload with the key, or starts
+// a new one with the unique upload ID
+func (s *Service) chunkProgress(key, id string) (*chunkProgress, error) {
+	p := &chunkProgress{state: &UploadState{ID: id}, acked: map[int64]int64{}}
+	if s.uploadStates == nil || key == "" {
+		return p, nil
+	}
+	p.store, p.key = s.uploadStates, key
+
+	state, err := p.store.Load(key)
+	if err != nil {
+		return nil, err
+	}
+	if state != nil {
+		p.state = state
+	}
+	return p, nil
+}
+
+// ack records the chunk acknowledged at offset, saving the contiguous bytes
+// acknowledged from the start
+func (p *chunkProgress) ack(offset, n int64) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.acked[offset] = n
+	for {
+		n, ok := p.acked[p.state.Sent]
+		if !ok {
+			break
+		}
+		delete(p.acked, p.state.Sent)
+		p.state.Sent += n
+	}
+
+	if p.store == nil {
+		return nil
+	}
+	p.state.UpdatedAt = time.Now()
+	return p.store.Save(p.key, p.state)
+}
+
+func (p *chunkProgress) finish() error {
+	if p.store == nil {
+		return nil
+	}
+	return p.store.Delete(p.key)
+}
+
+func (s *Service) uploadChunk(ctx context.Context, opts *UploadOptions, id string, body BodyFunc, offset, n int64, total string) (*Response, error) {
+	r, err := s.newUploadRequest(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	r.open = body
+	r.header.Set("X-Unique-Upload-Id", id)
+	r.header.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%s", offset, offset+n-1, total))
+
+	return s.do(r)
+}
+
+// sectionBody reads n bytes of ra from offset, as many times as needed
+func sectionBody(ra io.ReaderAt, offset, n int64) BodyFunc {
+	return func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(io.NewSectionReader(ra, offset, n)), nil
+	}
+}
+
+// readerAtRange returns the range left to read of a reader supporting
+// random access, if its size is known
+func readerAtRange(reader io.Reader) (io.ReaderAt, int64, int64, bool) {
+	ra, ok := reader.(io.ReaderAt)
+	if !ok {
+		return nil, 0, 0, false
+	}
+
+	var end int64
+	switch r := reader.(type) {
+	case interface{ Size() int64 }:
+		end = r.Size()
+	case interface{ Stat() (fs.FileInfo, error) }:
+		info, err := r.Stat()
+		if err != nil || !info.Mode().IsRegular() {
+			return nil, 0, 0, false
+		}
+		end = info.Size()
+	default:
+		return nil, 0, 0, false
+	}
+
+	var start int64
+	if seeker, ok := reader.(io.Seeker); ok {
+		var err error
+		if start, err = seeker.Seek(0, io.SeekCurrent); err != nil {
+			return nil, 0, 0, false
+		}
+	}
+	return ra, start, end, true
+}
+
+func randomID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,128 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without calling the API while the circuit breaker is open.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+// CircuitBreaker stops calling the API after consecutive server errors or timeouts.
+// Once open, it lets a single trial request through after the cooldown (half-open):
+// a success closes the circuit again, a failure keeps it open for another cooldown.
+// It is safe for concurrent use and may be shared by several services.
+type CircuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	failures  int
+	state     circuitState
+	openedAt  time.Time
+	now       func() time.Time
+}
+
+// NewCircuitBreaker opens after threshold consecutive failures, for cooldown.
+func NewCircuitBreaker(threshold int, cooldown time.Duration) *CircuitBreaker {
+	if threshold < 1 {
+		threshold = 1
+	}
+	return &CircuitBreaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		now:       time.Now,
+	}
+}
+
+// WithCircuitBreaker guards every request of the Service with cb.
+func WithCircuitBreaker(cb *CircuitBreaker) Option {
+	return func(s *Service) error {
+		s.breaker = cb
+		return nil
+	}
+}
+
+// Open reports whether requests are currently rejected
+func (cb *CircuitBreaker) Open() bool {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	return cb.state == circuitOpen && cb.now().Sub(cb.openedAt) < cb.cooldown
+}
+
+func (cb *CircuitBreaker) allow() error {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	switch cb.state {
+	case circuitOpen:
+		if cb.now().Sub(cb.openedAt) < cb.cooldown {
+			return ErrCircuitOpen
+		}
+		cb.state = circuitHalfOpen
+		return nil
+	case circuitHalfOpen:
+		// A trial request is already in flight
+		return ErrCircuitOpen
+	}
+	return nil
+}
+
+func (cb *CircuitBreaker) record(resp *http.Response, err error) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	unavailable := isUnavailable(resp, err)
+	if resp == nil && !unavailable {
+		// Never reached the API, nothing learnt about it. A trial is let
+		// through again, its cooldown already elapsed
+		if cb.state == circuitHalfOpen {
+			cb.state = circuitOpen
+		}
+		return
+	}
+
+	if !unavailable {
+		cb.failures = 0
+		cb.state = circuitClosed
+		return
+	}
+
+	cb.failures++
+	if cb.state == circuitHalfOpen || cb.failures >= cb.threshold {
+		cb.state = circuitOpen
+		cb.openedAt = cb.now()
+	}
+}
+
+// isUnavailable tells whether the outcome of a request hints at a degraded API
+func isUnavailable(resp *http.Response, err error) bool {
+	if err != nil {
+		// Only network failures count, not the ones raised before sending
+		// (rate limiter, token source) nor cancelled requests
+		if errors.Is(err, context.Canceled) {
+			return false
+		}
+		var ne net.Error
+		return errors.As(err, &ne) || errors.Is(err, context.DeadlineExceeded)
+	}
+	return resp.StatusCode >= http.StatusInternalServerError
+}
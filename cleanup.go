@@ -0,0 +1,60 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// CleanupByTag deletes the image, video and raw assets having tag that were
+// created more than olderThan ago under the folder prefix, returning the
+// deleted public IDs.
+// Deletions are batched and go through the AdminAPI limiter, see
+// WithRateLimit. It stops at the first error, returning the assets
+// deleted so far.
+func (s *Service) CleanupByTag(ctx context.Context, tag string, olderThan time.Duration) ([]string, error) {
+	before := s.now().Add(-olderThan)
+
+	var deleted []string
+	for _, rt := range []string{"image", "video", "raw"} {
+		// Collected first, deleting would shift the pages
+		expired := map[string][]string{} // Public IDs by delivery type
+		it := s.IterateResourcesByTag(ctx, rt, tag, &ResourcesQuery{MaxResults: 500})
+		for it.Next() {
+			// Tags are shared by the whole cloud, beyond the folder prefix
+			if r := it.Resource(); r.CreatedAt.Before(before) && strings.HasPrefix(r.PublicID, s.folderPrefix) {
+				expired[r.Type] = append(expired[r.Type], r.PublicID)
+			}
+		}
+		if err := it.Err(); err != nil {
+			return deleted, err
+		}
+
+		for typ, ids := range expired {
+			for len(ids) > 0 {
+				n := len(ids)
+				if n > maxDeleteIDs {
+					n = maxDeleteIDs
+				}
+
+				r, err := s.deleteResources(ctx, rt, typ, ids[:n])
+				if err != nil {
+					return deleted, err
+				}
+				for id, status := range r.Deleted {
+					if status == "deleted" {
+						deleted = append(deleted, id)
+					}
+				}
+				ids = ids[n:]
+			}
+		}
+	}
+	return deleted, nil
+}
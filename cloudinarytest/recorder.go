@@ -0,0 +1,354 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package cloudinarytest provides utilities for testing code calling
+// the Cloudinary API without credentials.
+//
+// A Recorder is an http.RoundTripper capturing real API interactions to a
+// JSON cassette file, with secrets redacted, and replaying them later. The
+// requests replayed must have the method, URL, body and fields recorded,
+// the redacted fields aside:
+//
+//	mode := cloudinarytest.Replay
+//	if os.Getenv("CLOUDINARY") != "" {
+//		mode = cloudinarytest.Record
+//	}
+//	rec, err := cloudinarytest.New("testdata/upload.json", mode, secrets)
+//	...
+//	defer rec.Save()
+//	s, err := cloudinary.Dial(uri, cloudinary.WithHTTPClient(rec.Client()))
+package cloudinarytest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Mode of a Recorder.
+type Mode int
+
+// Recorder modes
+const (
+	// Replay serves the recorded interactions, without network access
+	Replay Mode = iota
+	// Record sends the requests to the API and records the interactions
+	Record
+)
+
+// Redacted replaces the request fields and headers that change on every
+// request or hold credentials.
+const Redacted = "REDACTED"
+
+// Request fields never recorded as is
+var redactedFields = map[string]bool{
+	"api_key":   true,
+	"signature": true,
+	"timestamp": true,
+}
+
+// Response headers worth recording
+var recordedHeaders = []string{
+	"Content-Type",
+	"X-Cld-Error",
+	"X-FeatureRateLimit-Limit",
+	"X-FeatureRateLimit-Remaining",
+	"X-FeatureRateLimit-Reset",
+}
+
+// Interaction is a recorded request and its response.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request as recorded, the content of uploaded files is left out.
+type Request struct {
+	Method string            `json:"method"`
+	URL    string            `json:"url"`
+	Form   map[string]string `json:"form,omitempty"`
+	Body   string            `json:"body,omitempty"`
+}
+
+// Response as recorded.
+type Response struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body"`
+}
+
+// Recorder records or replays API interactions, it is safe for concurrent use.
+type Recorder struct {
+	// Transport sends the requests in Record mode, http.DefaultTransport if nil
+	Transport http.RoundTripper
+
+	path    string
+	mode    Mode
+	secrets *strings.Replacer
+
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// New returns a recorder of the cassette at path.
+// The secrets values, e.g. API key, secret and cloud name, are replaced by
+// {{name}} in everything recorded. In Replay mode, the cassette is loaded and
+// the secrets given are the ones of the replaying Service, so that requests
+// still match the recorded ones.
+func New(path string, mode Mode, secrets map[string]string) (*Recorder, error) {
+	pairs := make([]string, 0, 2*len(secrets))
+	for name, value := range secrets {
+		if value != "" {
+			pairs = append(pairs, value, "{{"+name+"}}")
+		}
+	}
+
+	r := &Recorder{
+		path:    path,
+		mode:    mode,
+		secrets: strings.NewReplacer(pairs...),
+	}
+
+	if mode == Replay {
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(b, &r.interactions); err != nil {
+			return nil, err
+		}
+		r.used = make([]bool, len(r.interactions))
+	}
+
+	return r, nil
+}
+
+// Client returns an HTTP client using the recorder
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// Interactions returns the recorded or loaded interactions
+func (r *Recorder) Interactions() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]Interaction(nil), r.interactions...)
+}
+
+// Save writes the recorded interactions to the cassette,
+// it does nothing in Replay mode.
+func (r *Recorder) Save() error {
+	if r.mode != Record {
+		return nil
+	}
+
+	r.mu.Lock()
+	b, err := json.MarshalIndent(r.interactions, "", "  ")
+	r.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(r.path, append(b, '\n'), 0644)
+}
+
+// RoundTrip records or replays req
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	recorded, body, err := r.request(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.mode == Replay {
+		return r.replay(req, recorded)
+	}
+
+	// Send the original request, body included
+	out := req.Clone(req.Context())
+	out.Body = ioutil.NopCloser(bytes.NewReader(body))
+	out.ContentLength = int64(len(body))
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	resp, err := transport.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	header := http.Header{}
+	for _, key := range recordedHeaders {
+		if v := resp.Header.Get(key); v != "" {
+			header.Set(key, r.secrets.Replace(v))
+		}
+	}
+
+	r.mu.Lock()
+	r.interactions = append(r.interactions, Interaction{
+		Request: recorded,
+		Response: Response{
+			StatusCode: resp.StatusCode,
+			Header:     header,
+			Body:       r.secrets.Replace(string(respBody)),
+		},
+	})
+	r.mu.Unlock()
+
+	resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+	return resp, nil
+}
+
+// request returns the redacted form of req along with its raw body
+func (r *Recorder) request(req *http.Request) (Request, []byte, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = ioutil.ReadAll(req.Body); err != nil {
+			return Request{}, nil, err
+		}
+		req.Body.Close()
+	}
+
+	u := *req.URL
+	u.User = nil
+	query := u.Query()
+	for key := range query {
+		if redactedFields[key] {
+			query.Set(key, Redacted)
+		}
+	}
+	u.RawQuery = query.Encode()
+
+	recorded := Request{
+		Method: req.Method,
+		URL:    r.secrets.Replace(u.String()),
+	}
+
+	mediaType, params, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	switch {
+	case mediaType == "multipart/form-data":
+		form, err := readMultipart(body, params["boundary"])
+		if err != nil {
+			return Request{}, nil, err
+		}
+		recorded.Form = r.redactForm(form)
+	case mediaType == "application/x-www-form-urlencoded":
+		values, err := url.ParseQuery(string(body))
+		if err != nil {
+			return Request{}, nil, err
+		}
+		form := map[string]string{}
+		for key := range values {
+			form[key] = values.Get(key)
+		}
+		recorded.Form = r.redactForm(form)
+	case len(body) > 0:
+		recorded.Body = r.secrets.Replace(string(body))
+	}
+
+	return recorded, body, nil
+}
+
+func (r *Recorder) redactForm(form map[string]string) map[string]string {
+	for key, value := range form {
+		if redactedFields[key] {
+			form[key] = Redacted
+		} else {
+			form[key] = r.secrets.Replace(value)
+		}
+	}
+	return form
+}
+
+// readMultipart returns the fields of a multipart body, files left out
+func readMultipart(body []byte, boundary string) (map[string]string, error) {
+	form := map[string]string{}
+	mr := multipart.NewReader(bytes.NewReader(body), boundary)
+	for {
+		part, err := mr.NextPart()
+		if err != nil {
+			if err == io.EOF {
+				return form, nil
+			}
+			return nil, err
+		}
+		if part.FileName() != "" {
+			continue
+		}
+		value, err := ioutil.ReadAll(part)
+		if err != nil {
+			return nil, err
+		}
+		form[part.FormName()] = string(value)
+	}
+}
+
+// replay returns the response of the first unused interaction matching
+// the method, URL, fields and body of the request, redacted ones aside
+func (r *Recorder) replay(req *http.Request, recorded Request) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, in := range r.interactions {
+		if r.used[i] || !in.Request.matches(recorded) {
+			continue
+		}
+		r.used[i] = true
+
+		header := http.Header{}
+		for key, values := range in.Response.Header {
+			header[key] = values
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", in.Response.StatusCode, http.StatusText(in.Response.StatusCode)),
+			StatusCode:    in.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          ioutil.NopCloser(strings.NewReader(in.Response.Body)),
+			ContentLength: int64(len(in.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, errors.New("cloudinarytest: no recorded interaction for " + recorded.Method + " " + recorded.URL)
+}
+
+// matches tells whether req is the same as the recorded request
+func (r Request) matches(req Request) bool {
+	if r.Method != req.Method || r.URL != req.URL || r.Body != req.Body || len(r.Form) != len(req.Form) {
+		return false
+	}
+	for key, value := range r.Form {
+		if v, ok := req.Form[key]; !ok || v != value {
+			return false
+		}
+	}
+	return true
+}
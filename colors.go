@@ -0,0 +1,56 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// Color is a color of an image palette.
+type Color struct {
+	Hex     string  // e.g. #F2F2F2
+	Percent float64 // Share of the image
+}
+
+// UnmarshalJSON decodes the ["#F2F2F2", 34.6] pairs of the API
+func (c *Color) UnmarshalJSON(b []byte) error {
+	var pair []interface{}
+	if err := json.Unmarshal(b, &pair); err != nil {
+		return err
+	}
+	if len(pair) != 2 {
+		return fmt.Errorf("invalid color %s", b)
+	}
+	hex, ok := pair[0].(string)
+	percent, ok2 := pair[1].(float64)
+	if !ok || !ok2 {
+		return fmt.Errorf("invalid color %s", b)
+	}
+	c.Hex, c.Percent = hex, percent
+	return nil
+}
+
+// Colors returns the palette of an upload type image, main colors first
+func (s *Service) Colors(ctx context.Context, publicID string) ([]Color, error) {
+	r, err := s.newAdminRequest(http.MethodGet, "resources/image/upload/"+escapePublicID(s.prefixed(publicID)), url.Values{"colors": {"true"}}, nil)
+	if err != nil {
+		return nil, err
+	}
+	r.ctx = ctx
+
+	var res struct {
+		Colors []Color `json:"colors"`
+	}
+	if err := s.call(r, &res); err != nil {
+		return nil, err
+	}
+	return res.Colors, nil
+}
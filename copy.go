@@ -0,0 +1,151 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// downloadTTL is how long the download URL given to the target cloud is valid
+const downloadTTL = time.Hour
+
+// DownloadOptions shape a private download URL, zero values are left out.
+type DownloadOptions struct {
+	ResourceType string // image (default), video or raw
+	Type         string // Delivery type, upload by default
+	Format       string // Converts the asset, e.g. jpg
+	// ExpiresAt is when the URL stops working, one hour from now if zero
+	ExpiresAt  time.Time
+	Attachment bool // Downloaded as a file by browsers
+}
+
+// PrivateDownloadURL returns the signed URL downloading an asset through
+// the API, whatever its delivery type, opts may be nil
+func (s *Service) PrivateDownloadURL(publicID string, opts *DownloadOptions) string {
+	if opts == nil {
+		opts = &DownloadOptions{}
+	}
+	rt := (&UploadOptions{ResourceType: opts.ResourceType}).resourceType()
+	expires := opts.ExpiresAt
+	if expires.IsZero() {
+		expires = s.signingTime().Add(downloadTTL)
+	}
+
+	params := map[string]string{
+		"public_id":  s.prefixed(publicID),
+		"expires_at": strconv.FormatInt(expires.Unix(), 10),
+	}
+	if opts.Type != "" {
+		params["type"] = opts.Type
+	}
+	if opts.Format != "" {
+		params["format"] = opts.Format
+	}
+	if opts.Attachment {
+		params["attachment"] = "true"
+	}
+
+	query := url.Values{}
+	for key, value := range s.signParams(params) {
+		query.Set(key, value)
+	}
+	return fmt.Sprintf(uploadAPIFmt, s.apiURL, s.cloudName, rt, "download") + "?" + query.Encode()
+}
+
+// CopyOptions are the optional parameters of CopyTo.
+type CopyOptions struct {
+	// ResourceType of the asset, found by public ID when empty for the
+	// upload type, image for the others
+	ResourceType string
+	Type         string // Delivery type, upload by default
+	// Upload holds the options of the upload into the target, its public
+	// ID, delivery type, tags and context are those of the asset if unset
+	Upload *UploadOptions
+}
+
+// CopyTo uploads an asset of the service into the target one, e.g. from a
+// staging cloud into the production one, preserving its public ID, tags
+// and context. The target fetches the asset itself from a private download
+// URL valid for an hour, the asset is never downloaded locally.
+// Public IDs are relative to the folder prefix of each service, if any.
+func (s *Service) CopyTo(ctx context.Context, target *Service, publicID string, opts *CopyOptions) (*Response, error) {
+	if err := ValidatePublicID(publicID); err != nil {
+		return nil, err
+	}
+	if opts == nil {
+		opts = &CopyOptions{}
+	}
+
+	res, err := s.copySource(ctx, s.prefixed(publicID), opts)
+	if err != nil {
+		return nil, err
+	}
+
+	up := UploadOptions{}
+	if opts.Upload != nil {
+		up = *opts.Upload
+	}
+	up.ResourceType = res.ResourceType
+	if up.Type == "" {
+		up.Type = res.Type
+	}
+	if up.PublicID == "" {
+		up.PublicID = strings.TrimPrefix(res.PublicID, s.folderPrefix)
+	}
+	if up.Tags == nil {
+		up.Tags = res.Tags
+	}
+	if up.Context == nil {
+		up.Context = res.Context
+	}
+
+	addr := s.PrivateDownloadURL(res.PublicID, &DownloadOptions{
+		ResourceType: res.ResourceType,
+		Type:         res.Type,
+		Format:       res.Format,
+	})
+	return target.Upload(ctx, addr, &up)
+}
+
+// copySource returns the details of the asset copied by CopyTo
+func (s *Service) copySource(ctx context.Context, publicID string, opts *CopyOptions) (*Resource, error) {
+	typ := opts.Type
+	if typ == "" {
+		typ = "upload"
+	}
+	rt := opts.ResourceType
+	if rt == "" || rt == "auto" {
+		if typ == "upload" {
+			return s.findResource(ctx, publicID)
+		}
+		rt = "image"
+	}
+
+	r, err := s.newAdminRequest(http.MethodGet, "resources/"+rt+"/"+typ+"/"+escapePublicID(publicID), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	r.ctx = ctx
+
+	res := &Resource{}
+	if err := s.call(r, res); err != nil {
+		return nil, err
+	}
+	if res.ResourceType == "" {
+		res.ResourceType = rt
+	}
+	if res.Type == "" {
+		res.Type = typ
+	}
+	return res, nil
+}
@@ -0,0 +1,163 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// Dedupe skips uploading content that already exists in the cloud, among
+// the assets of the same resource and delivery types under the folder
+// prefix. Content is identified by its MD5 hash, which Cloudinary exposes
+// as the etag. Perceptual hashing isn't supported: only byte-identical
+// files are found, the same image encoded or resized differently is
+// uploaded again.
+type Dedupe struct {
+	// Manifest remembers the public ID of the content already uploaded, may be nil
+	Manifest Manifest
+	// Search looks the hash up among the existing assets when not in the manifest
+	Search bool
+}
+
+// Manifest stores the public ID of uploaded content by key, made of the
+// resource type, the delivery type and the hash of the content, e.g.
+// image/upload/<md5>. Implementations must be safe for concurrent use.
+type Manifest interface {
+	// Lookup returns the public ID of the content, or "" when unknown
+	Lookup(key string) (string, error)
+	// Store records the public ID of the content
+	Store(key, publicID string) error
+}
+
+// MemoryManifest is a Manifest kept in memory
+type MemoryManifest struct {
+	mu  sync.RWMutex
+	ids map[string]string
+}
+
+// NewMemoryManifest returns an empty MemoryManifest
+func NewMemoryManifest() *MemoryManifest {
+	return &MemoryManifest{ids: map[string]string{}}
+}
+
+// Lookup implements Manifest
+func (m *MemoryManifest) Lookup(key string) (string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.ids[key], nil
+}
+
+// Store implements Manifest
+func (m *MemoryManifest) Store(key, publicID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.ids[key] = publicID
+	return nil
+}
+
+// dedupeKey returns the Manifest key of the content hash
+func dedupeKey(hash string, opts *UploadOptions) string {
+	return opts.resourceType() + "/" + opts.deliveryType() + "/" + hash
+}
+
+// dedupe returns the existing asset with the content hash, or nil
+func (s *Service) dedupe(ctx context.Context, d *Dedupe, hash string, opts *UploadOptions) (*Response, error) {
+	if d.Manifest != nil {
+		publicID, err := d.Manifest.Lookup(dedupeKey(hash, opts))
+		if err != nil {
+			return nil, err
+		}
+		if publicID != "" {
+			return &Response{PublicID: publicID, ResourceType: opts.resourceType(), Duplicate: true}, nil
+		}
+	}
+
+	if d.Search {
+		expr := fmt.Sprintf("etag=%s AND type=%s", hash, opts.deliveryType())
+		if rt := opts.resourceType(); rt != "auto" {
+			expr += " AND resource_type=" + rt
+		}
+		if s.folderPrefix != "" {
+			expr += " AND public_id:" + s.folderPrefix + "*"
+		}
+		res, err := s.SearchContext(ctx, &SearchQuery{Expression: expr, MaxResults: 1})
+		if err != nil {
+			return nil, err
+		}
+		if len(res.Resources) > 0 {
+			r := res.Resources[0]
+			return &Response{
+				PublicID:     r.PublicID,
+				SecureURL:    r.SecureURL,
+				Version:      r.Version,
+				Format:       r.Format,
+				ResourceType: r.ResourceType,
+				Size:         r.Size,
+				ETag:         r.ETag,
+				Duplicate:    true,
+			}, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// contentHash returns the hex MD5 and the size of file,
+// or "" when it can't be read twice, e.g. a remote file
+func contentHash(file interface{}) (string, int64, error) {
+	h := md5.New()
+	var n int64
+	var err error
+	switch f := file.(type) {
+	case string:
+		if remoteFile.MatchString(f) {
+			return "", 0, nil
+		}
+		var fd *os.File
+		if fd, err = os.Open(f); err != nil {
+			return "", 0, err
+		}
+		defer fd.Close()
+		n, err = copyBuffer(h, fd)
+	case []byte:
+		n, err = copyBuffer(h, bytes.NewReader(f))
+	case BodyFunc:
+		n, err = hashBody(h, f)
+	case func() (io.ReadCloser, error):
+		n, err = hashBody(h, f)
+	case io.ReadSeeker:
+		var offset int64
+		if offset, err = f.Seek(0, io.SeekCurrent); err != nil {
+			return "", 0, err
+		}
+		if n, err = copyBuffer(h, f); err == nil {
+			_, err = f.Seek(offset, io.SeekStart)
+		}
+	default:
+		return "", 0, nil
+	}
+	if err != nil {
+		return "", 0, err
+	}
+	return hex.EncodeToString(h.Sum(nil)), n, nil
+}
+
+func hashBody(h io.Writer, open BodyFunc) (int64, error) {
+	body, err := open()
+	if err != nil {
+		return 0, err
+	}
+	defer body.Close()
+	return copyBuffer(h, body)
+}
@@ -0,0 +1,63 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import (
+	"errors"
+	"reflect"
+)
+
+// WithDefaultUploadOptions applies opts to every upload, the options given
+// to a call take precedence field by field. Context and Extra are merged,
+// booleans can only be turned on, use the *bool fields to turn one off per
+// call. PublicID and IdempotencyKey are specific to a call and ignored.
+func WithDefaultUploadOptions(opts *UploadOptions) Option {
+	return func(s *Service) error {
+		if opts == nil {
+			return errors.New("nil default upload options")
+		}
+		d := *opts
+		d.PublicID, d.IdempotencyKey = "", ""
+		s.uploadDefaults = &d
+		return nil
+	}
+}
+
+// uploadOptions returns opts completed with the default upload options,
+// opts itself is left untouched
+func (s *Service) uploadOptions(opts *UploadOptions) *UploadOptions {
+	if s.uploadDefaults == nil {
+		return opts
+	}
+	if opts == nil {
+		d := *s.uploadDefaults
+		return &d
+	}
+
+	merged := *opts
+	m := reflect.ValueOf(&merged).Elem()
+	d := reflect.ValueOf(s.uploadDefaults).Elem()
+	for i := 0; i < m.NumField(); i++ {
+		f, def := m.Field(i), d.Field(i)
+		switch {
+		case def.IsZero():
+		case f.Kind() == reflect.Map && !f.IsZero():
+			// Merged into a copy, the caller's map is left untouched
+			union := reflect.MakeMap(f.Type())
+			for _, src := range []reflect.Value{def, f} {
+				iter := src.MapRange()
+				for iter.Next() {
+					union.SetMapIndex(iter.Key(), iter.Value())
+				}
+			}
+			f.Set(union)
+		case f.IsZero():
+			f.Set(def)
+		}
+	}
+	return &merged
+}
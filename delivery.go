@@ -0,0 +1,169 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import (
+	"crypto/sha1"
+	"encoding/base64"
+	"strconv"
+	"strings"
+)
+
+const deliveryBaseURL = "https://res.cloudinary.com"
+
+// AutoTransformation lets Cloudinary pick the best format, quality and pixel
+// density for each client
+const AutoTransformation = "f_auto,q_auto,dpr_auto"
+
+// WithDefaultTransformation appends t, e.g. AutoTransformation, to the chain
+// of every image and video URL built by the service, unless opted out
+func WithDefaultTransformation(t string) Option {
+	return func(s *Service) error {
+		s.urlTransformation = strings.Trim(t, "/")
+		return nil
+	}
+}
+
+// URLOptions shape a delivery URL, zero values are left out.
+type URLOptions struct {
+	// ResourceType is image (default), video, raw, or auto for the type
+	// picked by an auto upload made through the service
+	ResourceType string
+	Type         string // Delivery type, upload by default
+	// Transformation is a chain of transformations,
+	// e.g. c_fill,w_100/e_sepia
+	Transformation string
+	Version        uint
+	Format         string // File extension, e.g. jpg
+	// Signed adds the signature required by the strict transformations
+	// and the authenticated delivery types
+	Signed bool
+	// NoDefault leaves out the transformation of WithDefaultTransformation
+	NoDefault bool
+	// NoVersion leaves out the version of the latest upload made through
+	// the service, used when Version is zero to bust the CDN cached copies
+	NoVersion bool
+}
+
+// URL returns the delivery URL of a resource, opts may be nil
+func (s *Service) URL(publicID string, opts *URLOptions) string {
+	o := URLOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	opts = &o
+
+	typ := opts.Type
+	if typ == "" {
+		typ = "upload"
+	}
+	if !remoteTypes[typ] {
+		publicID = s.prefixed(publicID)
+	}
+
+	rt := s.deliveryType(publicID, opts.ResourceType)
+	if opts.Version == 0 && !opts.NoVersion {
+		opts.Version = s.knownVersion(rt, typ, publicID)
+	}
+	if s.urlTransformation != "" && rt != "raw" && !opts.NoDefault {
+		if opts.Transformation == "" {
+			opts.Transformation = s.urlTransformation
+		} else {
+			opts.Transformation += "/" + s.urlTransformation
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString(s.deliveryURL)
+	sb.WriteString("/")
+	sb.WriteString(s.cloudName)
+	sb.WriteString("/")
+	sb.WriteString(rt)
+	sb.WriteString("/")
+	sb.WriteString(typ)
+	sb.WriteString("/")
+	if opts.Signed {
+		sb.WriteString(s.urlSignature(publicID, opts))
+		sb.WriteString("/")
+	}
+	if opts.Transformation != "" {
+		sb.WriteString(opts.Transformation)
+		sb.WriteString("/")
+	}
+	if opts.Version > 0 {
+		sb.WriteString("v")
+		sb.WriteString(strconv.FormatUint(uint64(opts.Version), 10))
+		sb.WriteString("/")
+	}
+	sb.WriteString(escapePublicID(publicID))
+	if opts.Format != "" {
+		sb.WriteString(".")
+		sb.WriteString(opts.Format)
+	}
+	// Left out of the signed URLs, as by the official SDKs
+	if s.analytics != "" && !opts.Signed {
+		sb.WriteString("?_a=")
+		sb.WriteString(s.analytics)
+	}
+	return sb.String()
+}
+
+// SmartURL returns the delivery URL of an image with AutoTransformation
+func (s *Service) SmartURL(publicID string) string {
+	if s.urlTransformation == AutoTransformation {
+		return s.URL(publicID, nil)
+	}
+	return s.URL(publicID, &URLOptions{Transformation: AutoTransformation})
+}
+
+// urlSignature returns the signature component of a delivery URL,
+// signing the transformation and the public ID but not the version
+func (s *Service) urlSignature(publicID string, opts *URLOptions) string {
+	toSign := publicID
+	if opts.Format != "" {
+		toSign += "." + opts.Format
+	}
+	if opts.Transformation != "" {
+		toSign = opts.Transformation + "/" + toSign
+	}
+	h := sha1.Sum([]byte(toSign + s.apiSecret))
+	return "s--" + base64.URLEncoding.EncodeToString(h[:])[:8] + "--"
+}
+
+// layerID returns the public ID of a resource as referred to by a layer,
+// with colons in place of the folder slashes
+func layerID(publicID string) string {
+	return strings.ReplaceAll(publicID, "/", ":")
+}
+
+// URLSet returns the URLs of an image at width for each pixel density,
+// e.g. 1, 2 and 3 for a srcset. The dpr of the default transformation,
+// if any, is replaced.
+func (s *Service) URLSet(publicID string, width int, dprs []float64) map[float64]string {
+	var defaults []string
+	for _, component := range strings.Split(s.urlTransformation, "/") {
+		var params []string
+		for _, p := range strings.Split(component, ",") {
+			if p != "" && !strings.HasPrefix(p, "dpr_") {
+				params = append(params, p)
+			}
+		}
+		if len(params) > 0 {
+			defaults = append(defaults, strings.Join(params, ","))
+		}
+	}
+
+	set := make(map[float64]string, len(dprs))
+	for _, dpr := range dprs {
+		t := "c_scale,w_" + strconv.Itoa(width) + ",dpr_" + strconv.FormatFloat(dpr, 'f', 1, 64)
+		if len(defaults) > 0 {
+			t += "/" + strings.Join(defaults, "/")
+		}
+		set[dpr] = s.URL(publicID, &URLOptions{Transformation: t, NoDefault: true})
+	}
+	return set
+}
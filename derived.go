@@ -0,0 +1,131 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// DeleteStaleDerived deletes the derived versions of the upload type resources
+// generated by the old transformations missing from the current ones, keeping the
+// originals and the derived versions still in use,
+// e.g. after a redesign so orphan derived storage isn't billed forever.
+// The public IDs are under the folder prefix, so are the deleted ones.
+func (s *Service) DeleteStaleDerived(ctx context.Context, publicIDs []string, resourceType string, old, current []Transformation) (*DeleteResult, error) {
+	kept := map[string]bool{}
+	for _, t := range current {
+		kept[t.Name] = true
+	}
+	var stale []string
+	for _, t := range old {
+		if !kept[t.Name] {
+			stale = append(stale, t.Name)
+		}
+	}
+
+	res := &DeleteResult{Deleted: map[string]string{}}
+	if len(stale) == 0 {
+		return res, nil
+	}
+
+	// Keyed by the prefixed public IDs, as the API answers
+	ids := make([]string, len(publicIDs))
+	for i, id := range publicIDs {
+		ids[i] = s.prefixed(id)
+	}
+	publicIDs = ids
+
+	for start := 0; start < len(publicIDs); start += maxDeleteIDs {
+		end := start + maxDeleteIDs
+		if end > len(publicIDs) {
+			end = len(publicIDs)
+		}
+		query := url.Values{
+			"public_ids[]":    publicIDs[start:end],
+			"keep_original":   {"true"},
+			"transformations": {strings.Join(stale, "|")},
+		}
+
+		for {
+			r, err := s.newAdminRequest(http.MethodDelete, "resources/"+resourceType+"/upload", query, nil)
+			if err != nil {
+				return nil, err
+			}
+			r.ctx = ctx
+			r.mutates = true
+
+			if s.skip(r) {
+				res.DryRun = true
+				for _, id := range publicIDs[start:end] {
+					res.Deleted[id] = "deleted"
+				}
+				break
+			}
+
+			page := &DeleteResult{}
+			if err := s.call(r, page); err != nil {
+				return res, err
+			}
+			for id, status := range page.Deleted {
+				res.Deleted[id] = status
+			}
+			if !page.Partial || page.NextCursor == "" {
+				break
+			}
+			query.Set("next_cursor", page.NextCursor)
+		}
+	}
+	return res, nil
+}
+
+// DerivedList is a page of derived versions.
+type DerivedList struct {
+	Derived    []Derived
+	NextCursor string
+}
+
+// DerivedResources lists a page of the derived versions of an upload type
+// resource, cursor is empty for the first one
+func (s *Service) DerivedResources(ctx context.Context, publicID, resourceType, cursor string) (*DerivedList, error) {
+	query := url.Values{"max_results": {"500"}}
+	if cursor != "" {
+		query.Set("derived_next_cursor", cursor)
+	}
+
+	r, err := s.newAdminRequest(http.MethodGet, "resources/"+resourceType+"/upload/"+escapePublicID(s.prefixed(publicID)), query, nil)
+	if err != nil {
+		return nil, err
+	}
+	r.ctx = ctx
+
+	res := &Resource{}
+	if err := s.call(r, res); err != nil {
+		return nil, err
+	}
+	return &DerivedList{Derived: res.Derived, NextCursor: res.DerivedNextCursor}, nil
+}
+
+// AllDerived lists every derived version of an upload type resource,
+// e.g. for storage audits
+func (s *Service) AllDerived(ctx context.Context, publicID, resourceType string) ([]Derived, error) {
+	var all []Derived
+	var cursor string
+	for {
+		page, err := s.DerivedResources(ctx, publicID, resourceType, cursor)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, page.Derived...)
+		if page.NextCursor == "" {
+			return all, nil
+		}
+		cursor = page.NextCursor
+	}
+}
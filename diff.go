@@ -0,0 +1,125 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+)
+
+// DiffReport compares local files with the remote assets.
+// Files are matched on their resource type and public ID, as PublishAssets
+// uploads them: the prefix followed by their path without extension, or
+// with it for raw files.
+type DiffReport struct {
+	OnlyLocal  []string   // Paths of the files missing remotely
+	OnlyRemote []Resource // Assets missing locally
+	Changed    []Changed  // Files whose content differs
+}
+
+// Changed is a local file differing from its remote asset.
+type Changed struct {
+	Path     string
+	Size     int64
+	ETag     string // MD5 of the local file
+	Resource Resource
+}
+
+// Diff reports how the files of fsys differ from the image, video and raw
+// assets of the upload type under the remotePrefix folder, without changing
+// anything.
+// Contents are compared by checksum when the API provides the etag,
+// by size otherwise.
+func (s *Service) Diff(ctx context.Context, fsys fs.FS, remotePrefix string) (*DiffReport, error) {
+	folder := strings.Trim(remotePrefix, "/")
+	if folder != "" {
+		folder += "/"
+	}
+
+	// The public IDs are unique per resource type only
+	remote := map[string]Resource{}
+	for _, rt := range []string{"image", "video", "raw"} {
+		it := s.IterateResources(ctx, rt, &ResourcesQuery{Type: "upload", Prefix: folder, MaxResults: 500})
+		for it.Next() {
+			r := *it.Resource()
+			r.ResourceType = rt
+			remote[rt+"/"+r.PublicID] = r
+		}
+		if err := it.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	report := &DiffReport{}
+	err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		ext := path.Ext(p)
+		rt, ok := assetTypes[strings.ToLower(ext)]
+		publicID := folder + strings.TrimSuffix(p, ext)
+		if !ok {
+			rt, publicID = "raw", folder+p
+		}
+		r, ok := remote[rt+"/"+publicID]
+		if !ok {
+			report.OnlyLocal = append(report.OnlyLocal, p)
+			return nil
+		}
+		delete(remote, rt+"/"+publicID)
+
+		etag, size, err := fileHash(fsys, p)
+		if err != nil {
+			return err
+		}
+		if (r.ETag != "" && !strings.EqualFold(r.ETag, etag)) || int64(r.Size) != size {
+			report.Changed = append(report.Changed, Changed{Path: p, Size: size, ETag: etag, Resource: r})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, r := range remote {
+		report.OnlyRemote = append(report.OnlyRemote, r)
+	}
+	sort.Slice(report.OnlyRemote, func(i, j int) bool {
+		a, b := report.OnlyRemote[i], report.OnlyRemote[j]
+		if a.PublicID != b.PublicID {
+			return a.PublicID < b.PublicID
+		}
+		return a.ResourceType < b.ResourceType
+	})
+
+	return report, nil
+}
+
+// fileHash returns the hex MD5 and the size of a file of fsys
+func fileHash(fsys fs.FS, name string) (string, int64, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return "", 0, err
+	}
+	defer f.Close()
+
+	h := md5.New()
+	n, err := copyBuffer(h, f)
+	if err != nil {
+		return "", 0, err
+	}
+	return hex.EncodeToString(h.Sum(nil)), n, nil
+}
@@ -0,0 +1,144 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import (
+	"bytes"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"io/ioutil"
+)
+
+// Downscale is a Preprocessor shrinking JPEG and PNG images to fit within
+// MaxWidth x MaxHeight, a zero bound is unlimited. JPEG images are also
+// re-encoded with Quality when set. The images are encoded anew without
+// their metadata, the EXIF orientation of JPEG files is applied first.
+// Other files, and images fitting within the bounds the re-encoding
+// doesn't make smaller, are left untouched.
+type Downscale struct {
+	MaxWidth  int
+	MaxHeight int
+	// Quality of the re-encoded JPEG images, defaults to 90
+	Quality int
+}
+
+// Process implements Preprocessor
+func (p Downscale) Process(r io.Reader) (io.Reader, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil || (format != "jpeg" && format != "png") {
+		return bytes.NewReader(data), nil
+	}
+
+	if format == "jpeg" {
+		if segments, err := jpegSegments(data); err == nil {
+			if o := jpegOrientation(segments); o > 1 {
+				img = orient(img, o)
+			}
+		}
+	}
+
+	b := img.Bounds()
+	w, h := fit(b.Dx(), b.Dy(), p.MaxWidth, p.MaxHeight)
+	resized := w != b.Dx() || h != b.Dy()
+	if !resized && (format != "jpeg" || p.Quality == 0) {
+		return bytes.NewReader(data), nil
+	}
+	if resized {
+		img = resize(img, w, h)
+	}
+
+	var buf bytes.Buffer
+	if format == "jpeg" {
+		quality := p.Quality
+		if quality == 0 {
+			quality = 90
+		}
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality})
+	} else {
+		err = (&png.Encoder{CompressionLevel: png.BestCompression}).Encode(&buf, img)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Oversized images are always resized, even if larger once encoded
+	if !resized && buf.Len() >= len(data) {
+		return bytes.NewReader(data), nil
+	}
+	return bytes.NewReader(buf.Bytes()), nil
+}
+
+// fit returns the size of w x h scaled down to fit within maxW x maxH,
+// keeping the aspect ratio
+func fit(w, h, maxW, maxH int) (int, int) {
+	scale := 1.0
+	if maxW > 0 && w > maxW {
+		scale = float64(maxW) / float64(w)
+	}
+	if maxH > 0 && float64(h)*scale > float64(maxH) {
+		scale = float64(maxH) / float64(h)
+	}
+	if scale == 1 {
+		return w, h
+	}
+
+	dw, dh := int(float64(w)*scale+0.5), int(float64(h)*scale+0.5)
+	if dw < 1 {
+		dw = 1
+	}
+	if dh < 1 {
+		dh = 1
+	}
+	return dw, dh
+}
+
+// resize scales img down to w x h, averaging the source pixels
+// covered by each destination pixel
+func resize(img image.Image, w, h int) *image.NRGBA {
+	b := img.Bounds()
+	sw, sh := b.Dx(), b.Dy()
+
+	dst := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		y0, y1 := y*sh/h, (y+1)*sh/h
+		if y1 == y0 {
+			y1++
+		}
+		for x := 0; x < w; x++ {
+			x0, x1 := x*sw/w, (x+1)*sw/w
+			if x1 == x0 {
+				x1++
+			}
+
+			var r, g, bl, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := img.At(b.Min.X+sx, b.Min.Y+sy).RGBA()
+					r, g, bl, a, n = r+uint64(cr), g+uint64(cg), bl+uint64(cb), a+uint64(ca), n+1
+				}
+			}
+
+			i := dst.PixOffset(x, y)
+			if a == 0 {
+				continue
+			}
+			// Un-premultiply the averaged color
+			dst.Pix[i] = uint8(r * 0xff / a)
+			dst.Pix[i+1] = uint8(g * 0xff / a)
+			dst.Pix[i+2] = uint8(bl * 0xff / a)
+			dst.Pix[i+3] = uint8(a / n >> 8)
+		}
+	}
+	return dst
+}
@@ -0,0 +1,87 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"io/ioutil"
+	"math/rand"
+	"testing"
+)
+
+func TestDownscale(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 400, 200))
+	rnd := rand.New(rand.NewSource(1))
+	for i := range img.Pix {
+		img.Pix[i] = uint8(rnd.Intn(256))
+	}
+	img.Set(0, 0, color.NRGBA{255, 0, 0, 255})
+	var buf bytes.Buffer
+	png.Encode(&buf, img)
+
+	r, err := Downscale{MaxWidth: 100, MaxHeight: 100}.Process(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, _ := ioutil.ReadAll(r)
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if format != "png" || cfg.Width != 100 || cfg.Height != 50 {
+		t.Errorf("expect a 100x50 png, got a %dx%d %s", cfg.Width, cfg.Height, format)
+	}
+
+	// Orientation is applied before fitting
+	r, err = Downscale{MaxWidth: 2}.Process(bytes.NewReader(exifJPEG(t, 8, 4, 6)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, _ = ioutil.ReadAll(r)
+	if cfg, _, err = image.DecodeConfig(bytes.NewReader(out)); err != nil || cfg.Width != 2 || cfg.Height != 4 {
+		t.Errorf("expect a 2x4 image, got %dx%d (%v)", cfg.Width, cfg.Height, err)
+	}
+
+	// Oversized images are resized even if larger once encoded
+	pal := image.NewPaletted(image.Rect(0, 0, 400, 400), color.Palette{color.Black, color.White})
+	buf.Reset()
+	png.Encode(&buf, pal)
+	r, err = Downscale{MaxWidth: 300}.Process(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, _ = ioutil.ReadAll(r)
+	if cfg, _, err = image.DecodeConfig(bytes.NewReader(out)); err != nil || cfg.Width != 300 || cfg.Height != 300 {
+		t.Errorf("expect a 300x300 image, got %dx%d (%v)", cfg.Width, cfg.Height, err)
+	}
+
+	// Small images and other files are left untouched
+	for _, data := range [][]byte{exifJPEG(t, 4, 2, 1), []byte("plain")} {
+		r, _ := Downscale{MaxWidth: 100}.Process(bytes.NewReader(data))
+		if out, _ := ioutil.ReadAll(r); !bytes.Equal(out, data) {
+			t.Error("expect the file to be left untouched")
+		}
+	}
+}
+
+func TestFit(t *testing.T) {
+	tests := []struct{ w, h, maxW, maxH, dw, dh int }{
+		{400, 200, 100, 0, 100, 50},
+		{400, 200, 0, 100, 200, 100},
+		{400, 200, 300, 50, 100, 50},
+		{400, 200, 0, 0, 400, 200},
+		{1000, 1, 10, 0, 10, 1},
+	}
+	for _, tt := range tests {
+		if dw, dh := fit(tt.w, tt.h, tt.maxW, tt.maxH); dw != tt.dw || dh != tt.dh {
+			t.Errorf("fit(%d, %d, %d, %d) = %d, %d, expect %d, %d", tt.w, tt.h, tt.maxW, tt.maxH, dw, dh, tt.dw, tt.dh)
+		}
+	}
+}
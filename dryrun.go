@@ -0,0 +1,52 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import "log"
+
+// DryRunOp is a destructive call skipped because of WithDryRun.
+type DryRunOp struct {
+	Method string
+	URL    string
+	Params map[string]string // Upload API parameters, unsigned
+}
+
+// WithDryRun skips the destructive calls (destroy, deletions, renames…)
+// and reports them to report instead, so cleanup jobs can be rehearsed.
+// The skipped calls succeed with the result they would likely have.
+// The standard logger is used when report is nil.
+func WithDryRun(report func(DryRunOp)) Option {
+	return func(s *Service) error {
+		if report == nil {
+			report = func(op DryRunOp) {
+				log.Printf("cloudinary: dry run: %s %s %v", op.Method, op.URL, op.Params)
+			}
+		}
+		s.dryRun = report
+		return nil
+	}
+}
+
+// skip reports the destructive request r and tells it must not be sent
+func (s *Service) skip(r *request) bool {
+	if s.dryRun == nil {
+		return false
+	}
+
+	op := DryRunOp{Method: r.method, URL: r.uri}
+	if len(r.query) > 0 {
+		op.URL += "?" + r.query.Encode()
+	}
+	if !r.admin {
+		op.Params = make(map[string]string, len(r.params))
+		for key, value := range r.params {
+			op.Params[key] = value
+		}
+	}
+	s.dryRun(op)
+	return true
+}
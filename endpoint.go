@@ -0,0 +1,55 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// apiVersion is the path of the API version under an upload prefix
+const apiVersion = "/v1_1"
+
+// WithUploadPrefix sends the API requests to another endpoint, e.g. the
+// regional https://api-eu.cloudinary.com or a test server
+func WithUploadPrefix(prefix string) Option {
+	return func(s *Service) error {
+		base, err := apiBase(prefix)
+		if err != nil {
+			return err
+		}
+		s.apiURL = base
+		return nil
+	}
+}
+
+// apiBase returns the base URL of the API under prefix
+func apiBase(prefix string) (string, error) {
+	u, err := url.Parse(prefix)
+	if err != nil {
+		return "", err
+	}
+	if (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return "", errors.New("upload prefix must be an http(s) URL")
+	}
+	return strings.TrimRight(prefix, "/") + apiVersion, nil
+}
+
+// uploadAPI returns the URL of an upload API action, under the upload
+// prefix of opts if any
+func (s *Service) uploadAPI(opts *UploadOptions, action string) (string, error) {
+	base := s.apiURL
+	if opts != nil && opts.UploadPrefix != "" {
+		var err error
+		if base, err = apiBase(opts.UploadPrefix); err != nil {
+			return "", err
+		}
+	}
+	return fmt.Sprintf(uploadAPIFmt, base, s.cloudName, opts.resourceType(), action), nil
+}
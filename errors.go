@@ -0,0 +1,168 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// APIError is returned when the API answers with an unexpected status.
+type APIError struct {
+	StatusCode int
+	Status     string
+	Message    string // From the X-Cld-Error header
+	// StringToSign is what was signed, followed by the API secret, when
+	// a signed request is refused, e.g. to diagnose an Invalid Signature
+	StringToSign string
+}
+
+// Error keeps the message of the errors returned before APIError,
+// misspelling included, for callers matching it
+func (e *APIError) Error() string {
+	msg := "request error: " + e.Status + " cld rrror: " + e.Message
+	if e.StringToSign != "" {
+		msg += " string to sign: " + e.StringToSign + "<api_secret>"
+	}
+	return msg
+}
+
+func newAPIError(resp *http.Response) error {
+	return &APIError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Message:    resp.Header.Get("X-Cld-Error"),
+	}
+}
+
+// ErrorCategory classifies the errors, see Category.
+type ErrorCategory int
+
+// Error categories
+const (
+	CategoryUnknown    ErrorCategory = iota
+	CategoryNetwork                  // Connection failures and timeouts
+	CategoryAuth                     // Invalid or revoked credentials, missing permissions
+	CategoryValidation               // Requests the API refuses as they are
+	CategoryNotFound                 // Missing resources
+	CategoryRateLimit                // Spent API allowance
+	CategoryServer                   // Failures of the API itself
+)
+
+var categoryNames = [...]string{"unknown", "network", "auth", "validation", "not found", "rate limit", "server"}
+
+func (c ErrorCategory) String() string {
+	if c < 0 || int(c) >= len(categoryNames) {
+		return "unknown"
+	}
+	return categoryNames[c]
+}
+
+// Category classifies an error returned by the service, e.g. for alerting
+func Category(err error) ErrorCategory {
+	var apiErr *APIError
+	var idErr *PublicIDError
+	var preErr *PreflightError
+	var ne net.Error
+	switch {
+	case err == nil:
+		return CategoryUnknown
+	case errors.Is(err, ErrRateLimited) || errors.Is(err, ErrBudgetExceeded):
+		return CategoryRateLimit
+	case errors.Is(err, ErrCircuitOpen):
+		return CategoryServer
+	case errors.As(err, &idErr) || errors.Is(err, ErrInvalidMetadata):
+		return CategoryValidation
+	case errors.As(err, &preErr) && preErr.Err == nil:
+		if preErr.StatusCode == http.StatusNotFound {
+			return CategoryNotFound
+		}
+		return CategoryValidation
+	case errors.As(err, &apiErr):
+		switch code := apiErr.StatusCode; {
+		case code == http.StatusUnauthorized || code == http.StatusForbidden:
+			return CategoryAuth
+		case code == http.StatusNotFound:
+			return CategoryNotFound
+		case code == http.StatusTooManyRequests || code == 420: // Cloudinary rate limit
+			return CategoryRateLimit
+		case code >= http.StatusInternalServerError:
+			return CategoryServer
+		case code >= http.StatusBadRequest:
+			return CategoryValidation
+		}
+	case errors.Is(err, context.Canceled):
+		return CategoryUnknown
+	case errors.Is(err, context.DeadlineExceeded) || errors.As(err, &ne):
+		return CategoryNetwork
+	}
+	return CategoryUnknown
+}
+
+// IsRetryable tells whether a failed operation may succeed when tried
+// again later, as opposed to a permanent failure
+func IsRetryable(err error) bool {
+	return transient(err)
+}
+
+// transient tells whether a failed request may succeed when sent again later
+func transient(err error) bool {
+	if errors.Is(err, ErrCircuitOpen) || errors.Is(err, ErrRateLimited) || errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= http.StatusInternalServerError ||
+			apiErr.StatusCode == http.StatusTooManyRequests ||
+			apiErr.StatusCode == 420 // Cloudinary rate limit
+	}
+
+	var ne net.Error
+	return errors.As(err, &ne) && !errors.Is(err, context.Canceled)
+}
+
+// ErrChecksumMismatch is matched by a *ChecksumError with errors.Is
+var ErrChecksumMismatch = errors.New("checksum mismatch")
+
+// ChecksumError is returned when the uploaded asset differs from the local file
+type ChecksumError struct {
+	PublicID     string
+	ETag         string // MD5 of the local file
+	Size         int64
+	UploadedETag string
+	UploadedSize int64
+}
+
+func (e *ChecksumError) Error() string {
+	return fmt.Sprintf("checksum mismatch for %s: local %s (%d bytes), uploaded %s (%d bytes)",
+		e.PublicID, e.ETag, e.Size, e.UploadedETag, e.UploadedSize)
+}
+
+// Is reports whether target is ErrChecksumMismatch
+func (e *ChecksumError) Is(target error) bool {
+	return target == ErrChecksumMismatch
+}
+
+// verifyChecksum compares the upload response with the local hash and size,
+// a field missing from the response is not compared
+func verifyChecksum(resp *Response, hash string, size int64) error {
+	if (resp.ETag == "" || strings.EqualFold(resp.ETag, hash)) && (resp.Size == 0 || int64(resp.Size) == size) {
+		return nil
+	}
+	return &ChecksumError{
+		PublicID:     resp.PublicID,
+		ETag:         hash,
+		Size:         size,
+		UploadedETag: resp.ETag,
+		UploadedSize: int64(resp.Size),
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import (
+	"context"
+	"path"
+	"strings"
+)
+
+// FolderStats sums up the assets under a folder, its subfolders included.
+type FolderStats struct {
+	Folder string
+	Count  int
+	Bytes  int64
+	// Formats breaks the assets down by format, the raw files by extension,
+	// those without one under the empty string
+	Formats map[string]AssetStats
+	// ResourceTypes breaks the assets down by image, video and raw
+	ResourceTypes map[string]AssetStats
+}
+
+// AssetStats are the number and total size of some assets.
+type AssetStats struct {
+	Count int
+	Bytes int64
+}
+
+// FolderStats walks the image, video and raw assets of the upload type
+// under folder, all of them under the folder prefix when empty, to count
+// them and sum up their size. Every asset is listed, which can take a while
+// for large folders.
+func (s *Service) FolderStats(ctx context.Context, folder string) (*FolderStats, error) {
+	folder = strings.Trim(folder, "/")
+	if folder == "" {
+		folder = s.folderPrefix
+	}
+	folder = strings.Trim(s.prefixed(folder), "/")
+	prefix := folder
+	if prefix != "" {
+		prefix += "/"
+	}
+
+	stats := &FolderStats{
+		Folder:        folder,
+		Formats:       map[string]AssetStats{},
+		ResourceTypes: map[string]AssetStats{},
+	}
+	for _, rt := range []string{"image", "video", "raw"} {
+		it := s.IterateResources(ctx, rt, &ResourcesQuery{Type: "upload", Prefix: prefix, MaxResults: 500})
+		for it.Next() {
+			r := it.Resource()
+			format := r.Format
+			if format == "" && rt == "raw" {
+				format = strings.TrimPrefix(path.Ext(r.PublicID), ".")
+			}
+
+			stats.Count++
+			stats.Bytes += int64(r.Size)
+			stats.Formats[format] = stats.Formats[format].add(r)
+			stats.ResourceTypes[rt] = stats.ResourceTypes[rt].add(r)
+		}
+		if err := it.Err(); err != nil {
+			return nil, err
+		}
+	}
+	return stats, nil
+}
+
+func (a AssetStats) add(r *Resource) AssetStats {
+	return AssetStats{Count: a.Count + 1, Bytes: a.Bytes + int64(r.Size)}
+}
@@ -0,0 +1,126 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/fs"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+)
+
+// FS returns a read-only file system of the delivered assets, e.g. to be
+// served with http.FS. Files are fetched from their delivery URL shaped by
+// opts, which may be nil, the extension of their name being the format,
+// except for raw files. Opened files are fully read in memory.
+func (s *Service) FS(ctx context.Context, opts *URLOptions) fs.FS {
+	if opts == nil {
+		opts = &URLOptions{}
+	}
+	return &assetFS{s: s, ctx: ctx, opts: *opts}
+}
+
+type assetFS struct {
+	s    *Service
+	ctx  context.Context
+	opts URLOptions
+}
+
+func (a *assetFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	if name == "." {
+		return &assetFile{Reader: bytes.NewReader(nil), info: assetInfo{name: ".", dir: true}}, nil
+	}
+
+	publicID, opts := assetPath(name, a.opts)
+	if err := ValidatePublicID(publicID); err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+
+	req, err := http.NewRequestWithContext(a.ctx, http.MethodGet, a.s.URL(publicID, &opts), nil)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	resp, err := a.s.client.Do(req)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	default:
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fmt.Errorf("unexpected status %s", resp.Status)}
+	}
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	modTime, _ := http.ParseTime(resp.Header.Get("Last-Modified"))
+
+	return &assetFile{
+		Reader: bytes.NewReader(b),
+		info:   assetInfo{name: path.Base(name), size: int64(len(b)), modTime: modTime},
+	}, nil
+}
+
+// assetPath returns the public ID and the delivery options of a file name,
+// its extension being the format except for raw files
+func assetPath(name string, opts URLOptions) (string, URLOptions) {
+	if opts.ResourceType == "raw" {
+		return name, opts
+	}
+	ext := path.Ext(name)
+	opts.Format = strings.TrimPrefix(ext, ".")
+	return strings.TrimSuffix(name, ext), opts
+}
+
+// assetFile is an asset read in memory, or the root directory
+type assetFile struct {
+	*bytes.Reader
+	info assetInfo
+}
+
+func (f *assetFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *assetFile) Close() error               { return nil }
+
+func (f *assetFile) Read(p []byte) (int, error) {
+	if f.info.dir {
+		return 0, &fs.PathError{Op: "read", Path: f.info.name, Err: fs.ErrInvalid}
+	}
+	return f.Reader.Read(p)
+}
+
+type assetInfo struct {
+	name    string
+	size    int64
+	modTime time.Time
+	dir     bool
+}
+
+func (i assetInfo) Name() string       { return i.name }
+func (i assetInfo) Size() int64        { return i.size }
+func (i assetInfo) ModTime() time.Time { return i.modTime }
+func (i assetInfo) IsDir() bool        { return i.dir }
+func (i assetInfo) Sys() interface{}   { return nil }
+
+func (i assetInfo) Mode() fs.FileMode {
+	if i.dir {
+		return fs.ModeDir | 0555
+	}
+	return 0444
+}
@@ -1,3 +1,3 @@
 module github.com/habibrosyad/go-cloudinary
 
-go 1.13
+go 1.16
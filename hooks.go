@@ -0,0 +1,96 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import (
+	"context"
+	"time"
+)
+
+// Hooks are called along the lifecycle of the assets, e.g. to audit the
+// operations or to keep a local database of the assets in sync.
+// They are called synchronously, nil hooks are skipped.
+type Hooks struct {
+	OnUploadStart   func(UploadEvent)
+	OnUploadSuccess func(UploadEvent, *Response)
+	OnUploadError   func(UploadEvent, error)
+	OnDestroy       func(DestroyEvent)
+}
+
+// UploadEvent describes an upload.
+type UploadEvent struct {
+	Context context.Context
+	// Options of the upload, defaults included, not to be modified
+	Options *UploadOptions
+	Start   time.Time
+	// Duration of the upload, zero on start
+	Duration time.Duration
+}
+
+// DestroyEvent describes a destroyed resource.
+type DestroyEvent struct {
+	Context      context.Context
+	PublicID     string
+	ResourceType string
+	Type         string
+	// Err is the failure of the destroy, if any
+	Err    error
+	DryRun bool
+}
+
+// WithHooks registers lifecycle hooks, it can be given several times
+func WithHooks(h Hooks) Option {
+	return func(s *Service) error {
+		s.hooks = append(s.hooks, h)
+		return nil
+	}
+}
+
+// track runs an upload between its lifecycle hooks, verifying its response
+// and remembering its version and the resource type picked for the auto ones
+func (s *Service) track(ctx context.Context, opts *UploadOptions, upload func() (*Response, error)) (*Response, error) {
+	if s.apiSecret != "" {
+		upload = s.verified(upload)
+	}
+	upload = s.rememberVersion(opts, upload)
+	if s.uploadOptions(opts).resourceType() == "auto" {
+		upload = s.rememberType(upload)
+	}
+	if len(s.hooks) == 0 {
+		return upload()
+	}
+
+	o := s.uploadOptions(opts)
+	if o == nil {
+		o = &UploadOptions{}
+	}
+	e := UploadEvent{Context: ctx, Options: o, Start: time.Now()}
+	for _, h := range s.hooks {
+		if h.OnUploadStart != nil {
+			h.OnUploadStart(e)
+		}
+	}
+
+	resp, err := upload()
+	e.Duration = time.Since(e.Start)
+	for _, h := range s.hooks {
+		if err != nil && h.OnUploadError != nil {
+			h.OnUploadError(e, err)
+		} else if err == nil && h.OnUploadSuccess != nil {
+			h.OnUploadSuccess(e, resp)
+		}
+	}
+	return resp, err
+}
+
+func (s *Service) destroyed(e DestroyEvent) {
+	for _, h := range s.hooks {
+		if h.OnDestroy != nil {
+			h.OnDestroy(e)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import (
+	"net/http"
+	"time"
+)
+
+// The response headers kept by HTTPInfo
+var httpInfoHeaders = []string{
+	"Date",
+	"X-Request-Id",
+	"X-Cld-Error",
+	"X-FeatureRateLimit-Limit",
+	"X-FeatureRateLimit-Remaining",
+	"X-FeatureRateLimit-Reset",
+	"Server-Timing",
+}
+
+// HTTPInfo describes the HTTP exchange behind a Response,
+// e.g. for logging latency metrics.
+type HTTPInfo struct {
+	StatusCode int
+	// Header holds the request ID, rate limit and timing headers
+	Header http.Header
+	// Duration until the response headers, retries included
+	Duration time.Duration
+}
+
+func newHTTPInfo(resp *http.Response, d time.Duration) *HTTPInfo {
+	h := http.Header{}
+	for _, key := range httpInfoHeaders {
+		if values := resp.Header.Values(key); len(values) > 0 {
+			h[key] = values
+		}
+	}
+	return &HTTPInfo{StatusCode: resp.StatusCode, Header: h, Duration: d}
+}
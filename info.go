@@ -0,0 +1,180 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import "image"
+
+// Info holds the results of the analysis add-ons run on upload or explicit,
+// each result is keyed by add-on name, e.g. google_tagging or adv_ocr.
+type Info struct {
+	Categorization map[string]Categorization `json:"categorization,omitempty"`
+	OCR            map[string]OCR            `json:"ocr,omitempty"`
+	Detection      Detection                 `json:"detection,omitempty"`
+	RawConvert     map[string]AddonStatus    `json:"raw_convert,omitempty"`
+	AutoChaptering *AddonStatus              `json:"auto_chaptering,omitempty"`
+}
+
+// AddonStatus is the status of an add-on whose result is a file.
+type AddonStatus struct {
+	Status string `json:"status"` // complete, pending or failed
+}
+
+// Categorization is the result of a tagging add-on.
+type Categorization struct {
+	Status string `json:"status"` // complete, pending or failed
+	Data   []Tag  `json:"data,omitempty"`
+}
+
+// Tag is a tag suggested by an add-on.
+type Tag struct {
+	Tag        string  `json:"tag"`
+	Confidence float64 `json:"confidence"`
+}
+
+// OCR is the result of a text recognition add-on.
+type OCR struct {
+	Status string    `json:"status"` // complete, pending or failed
+	Data   []OCRPage `json:"data,omitempty"`
+}
+
+// Text returns the full text recognized on every page
+func (o OCR) Text() string {
+	var text string
+	for _, page := range o.Data {
+		text += page.FullTextAnnotation.Text
+	}
+	return text
+}
+
+// OCRPage is the text recognized within an image or a page.
+type OCRPage struct {
+	TextAnnotations    []TextAnnotation `json:"textAnnotations,omitempty"`
+	FullTextAnnotation struct {
+		Text string `json:"text"`
+	} `json:"fullTextAnnotation"`
+}
+
+// TextAnnotation is a block of recognized text, the first one of a page
+// holds the whole text.
+type TextAnnotation struct {
+	Locale       string `json:"locale,omitempty"`
+	Description  string `json:"description"`
+	BoundingPoly struct {
+		Vertices []Vertex `json:"vertices"`
+	} `json:"boundingPoly"`
+}
+
+// Vertex is a point of an image, in pixels.
+type Vertex struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// Detection holds the results of the detection add-ons.
+type Detection struct {
+	ObjectDetection *ObjectDetection `json:"object_detection,omitempty"`
+	Captioning      *Captioning      `json:"captioning,omitempty"`
+	AdvFace         *AdvFace         `json:"adv_face,omitempty"`
+}
+
+// AdvFace is the result of the advanced facial attributes add-on.
+type AdvFace struct {
+	Status string `json:"status"` // complete, pending or failed
+	Data   []Face `json:"data,omitempty"`
+}
+
+// Face is a face detected by the advanced facial attributes add-on.
+type Face struct {
+	BoundingBox struct {
+		TopLeft struct {
+			X float64 `json:"x"`
+			Y float64 `json:"y"`
+		} `json:"top_left"`
+		Size struct {
+			Width  float64 `json:"width"`
+			Height float64 `json:"height"`
+		} `json:"size"`
+	} `json:"bounding_box"`
+	Attributes FaceAttributes `json:"attributes"`
+	// FacialLandmarks are the points of the face by name, e.g. eye_left
+	FacialLandmarks map[string]map[string]Point `json:"facial_landmarks,omitempty"`
+}
+
+// Rect returns the bounding box of the face, e.g. to crop it
+func (f Face) Rect() image.Rectangle {
+	b := f.BoundingBox
+	x, y := int(b.TopLeft.X), int(b.TopLeft.Y)
+	return image.Rect(x, y, x+int(b.Size.Width+0.5), y+int(b.Size.Height+0.5))
+}
+
+// FaceAttributes are the attributes estimated for a face.
+type FaceAttributes struct {
+	Age        float64            `json:"age"`
+	Gender     string             `json:"gender"`
+	Smile      float64            `json:"smile"`
+	Glasses    string             `json:"glasses"`
+	Emotion    map[string]float64 `json:"emotion,omitempty"`     // Confidence by emotion
+	FacialHair map[string]float64 `json:"facial_hair,omitempty"` // e.g. beard or moustache
+	HeadPose   struct {
+		Pitch float64 `json:"pitch"`
+		Roll  float64 `json:"roll"`
+		Yaw   float64 `json:"yaw"`
+	} `json:"head_pose"`
+	Blur struct {
+		BlurLevel string  `json:"blur_level"`
+		Value     float64 `json:"value"`
+	} `json:"blur"`
+}
+
+// Point is a landmark of a face, in pixels.
+type Point struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// Captioning is the result of the captioning add-on.
+type Captioning struct {
+	Status string `json:"status"` // complete, pending or failed
+	Data   struct {
+		Caption string `json:"caption"`
+	} `json:"data"`
+	ModelVersion float64 `json:"model_version,omitempty"`
+}
+
+// Caption returns the generated caption, e.g. for alt texts,
+// or "" when not available yet
+func (i *Info) Caption() string {
+	if i == nil || i.Detection.Captioning == nil || i.Detection.Captioning.Status != "complete" {
+		return ""
+	}
+	return i.Detection.Captioning.Data.Caption
+}
+
+// ObjectDetection is the result of an object detection add-on.
+type ObjectDetection struct {
+	Status string `json:"status"` // complete, pending or failed
+	// Data holds the results by model, e.g. coco, cld-fashion or
+	// any other model named in UploadOptions.Detection
+	Data map[string]DetectionModel `json:"data,omitempty"`
+}
+
+// DetectionModel is the result of a detection model.
+type DetectionModel struct {
+	ModelName    string `json:"model_name"`
+	ModelVersion int    `json:"model_version"`
+	// Tags are the objects detected by category, e.g. person or dog
+	Tags map[string][]DetectedObject `json:"tags"`
+}
+
+// DetectedObject is an object located on an image.
+type DetectedObject struct {
+	// BoundingBox is x, y, width and height in pixels
+	BoundingBox []float64              `json:"bounding-box"`
+	Confidence  float64                `json:"confidence"`
+	Categories  []string               `json:"categories,omitempty"`
+	Attributes  map[string]interface{} `json:"attributes,omitempty"`
+}
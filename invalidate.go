@@ -0,0 +1,68 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// WaitServed polls the delivery URL of resp every interval until the CDN
+// serves the uploaded version, i.e. answers with its etag when known.
+// It is meant for cache-sensitive deployments uploading with Invalidate.
+func (s *Service) WaitServed(ctx context.Context, resp *Response, interval time.Duration) error {
+	if resp == nil || resp.SecureURL == "" {
+		return errors.New("no delivery URL to check")
+	}
+
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		served, err := s.served(ctx, resp.SecureURL, resp.ETag)
+		if err != nil || served {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// served tells whether the delivery URL answers with the etag,
+// any successful answer will do when etag is empty
+func (s *Service) served(ctx context.Context, uri, etag string) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, uri, nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Cache-Control", "no-cache")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return false, ctx.Err()
+		}
+		// The CDN may not know the new version yet
+		return false, nil
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, nil
+	}
+	if etag == "" {
+		return true, nil
+	}
+	// Weak or quoted, the CDN etag holds the MD5
+	return strings.Contains(strings.ToLower(resp.Header.Get("ETag")), strings.ToLower(etag)), nil
+}
@@ -0,0 +1,207 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// ResourceIterator walks every resource of a listing or search, fetching the
+// next pages as needed. Pages are decoded incrementally, only the current
+// resource is held in memory, never a whole page.
+//
+//	it := s.IterateResources(ctx, "image", nil)
+//	defer it.Close()
+//	for it.Next() {
+//		r := it.Resource()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type ResourceIterator struct {
+	s    *Service
+	ctx  context.Context
+	page func(cursor string) (*request, error)
+
+	body    io.ReadCloser
+	dec     *json.Decoder
+	inArray bool
+	cursor  string
+	started bool
+
+	cur Resource
+	err error
+}
+
+// IterateResources walks all the resources of a listing, q may be nil
+func (s *Service) IterateResources(ctx context.Context, resourceType string, q *ResourcesQuery) *ResourceIterator {
+	path := "resources/" + resourceType
+	if q != nil && q.Type != "" {
+		path += "/" + q.Type
+	}
+
+	return s.iterate(ctx, func(cursor string) (*request, error) {
+		query := q.values()
+		if cursor != "" {
+			query.Set("next_cursor", cursor)
+		}
+		return s.newAdminRequest(http.MethodGet, path, query, nil)
+	})
+}
+
+// IterateResourcesByTag walks all the resources having the given tag, q may be nil
+func (s *Service) IterateResourcesByTag(ctx context.Context, resourceType, tag string, q *ResourcesQuery) *ResourceIterator {
+	path := "resources/" + resourceType + "/tags/" + url.PathEscape(tag)
+
+	return s.iterate(ctx, func(cursor string) (*request, error) {
+		query := q.values()
+		if cursor != "" {
+			query.Set("next_cursor", cursor)
+		}
+		return s.newAdminRequest(http.MethodGet, path, query, nil)
+	})
+}
+
+// IterateSearch walks all the resources matching a search, q may be nil
+func (s *Service) IterateSearch(ctx context.Context, q *SearchQuery) *ResourceIterator {
+	return s.iterate(ctx, func(cursor string) (*request, error) {
+		page := SearchQuery{}
+		if q != nil {
+			page = *q
+		}
+		page.NextCursor = cursor
+		return s.newAdminRequest(http.MethodPost, "resources/search", nil, &page)
+	})
+}
+
+func (s *Service) iterate(ctx context.Context, page func(cursor string) (*request, error)) *ResourceIterator {
+	return &ResourceIterator{s: s, ctx: ctx, page: page}
+}
+
+// Next advances to the next resource, it returns false when
+// there are no more resources or an error occurred.
+func (it *ResourceIterator) Next() bool {
+	for it.err == nil {
+		if it.dec == nil {
+			if it.started && it.cursor == "" {
+				return false
+			}
+			it.err = it.fetch()
+			continue
+		}
+
+		if it.inArray {
+			if it.dec.More() {
+				it.cur = Resource{}
+				if it.err = it.dec.Decode(&it.cur); it.err != nil {
+					break
+				}
+				return true
+			}
+			// Closing ]
+			if _, it.err = it.dec.Token(); it.err != nil {
+				break
+			}
+			it.inArray = false
+		}
+
+		it.err = it.seek()
+	}
+
+	it.Close()
+	return false
+}
+
+// Resource returns a copy of the current resource, which can be kept
+// once Next moves on
+func (it *ResourceIterator) Resource() *Resource {
+	r := it.cur
+	return &r
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *ResourceIterator) Err() error {
+	return it.err
+}
+
+// Close releases the page being read, it is only needed when
+// the iteration is stopped before Next returns false.
+func (it *ResourceIterator) Close() error {
+	if it.body == nil {
+		return nil
+	}
+	err := it.body.Close()
+	it.body, it.dec = nil, nil
+	return err
+}
+
+func (it *ResourceIterator) fetch() error {
+	r, err := it.page(it.cursor)
+	if err != nil {
+		return err
+	}
+	if it.ctx != nil {
+		r.ctx = it.ctx
+	}
+
+	body, err := it.s.open(r)
+	if err != nil {
+		return err
+	}
+
+	it.started = true
+	it.cursor = ""
+	it.body = body
+	it.dec = json.NewDecoder(body)
+
+	if t, err := it.dec.Token(); err != nil {
+		return err
+	} else if t != json.Delim('{') {
+		return errors.New("invalid response")
+	}
+	return nil
+}
+
+// seek reads the fields of the page until the resources array,
+// the page is closed once entirely read.
+func (it *ResourceIterator) seek() error {
+	for it.dec.More() {
+		t, err := it.dec.Token()
+		if err != nil {
+			return err
+		}
+
+		switch t {
+		case "resources":
+			if t, err := it.dec.Token(); err != nil {
+				return err
+			} else if t != json.Delim('[') {
+				return errors.New("invalid response")
+			}
+			it.inArray = true
+			return nil
+		case "next_cursor":
+			if err := it.dec.Decode(&it.cursor); err != nil {
+				return err
+			}
+		default:
+			var skip json.RawMessage
+			if err := it.dec.Decode(&skip); err != nil {
+				return err
+			}
+		}
+	}
+
+	// End of the page
+	return it.Close()
+}
@@ -0,0 +1,50 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// JSONDecoder decodes the JSON responses of the API into v, e.g. with a
+// faster library than encoding/json for high-volume listings.
+// It must honor the json.Unmarshaler implementations.
+type JSONDecoder interface {
+	Decode(r io.Reader, v interface{}) error
+}
+
+// JSONDecoderFunc adapts a function to a JSONDecoder, e.g.
+//
+//	func(r io.Reader, v interface{}) error { return sonic.ConfigStd.NewDecoder(r).Decode(v) }
+type JSONDecoderFunc func(r io.Reader, v interface{}) error
+
+// Decode calls f(r, v)
+func (f JSONDecoderFunc) Decode(r io.Reader, v interface{}) error {
+	return f(r, v)
+}
+
+// stdJSON is the default decoder
+var stdJSON = JSONDecoderFunc(func(r io.Reader, v interface{}) error {
+	return json.NewDecoder(r).Decode(v)
+})
+
+// WithJSONDecoder decodes the responses with d instead of encoding/json.
+// ResourceIterator streams its pages with encoding/json regardless.
+func WithJSONDecoder(d JSONDecoder) Option {
+	return func(s *Service) error {
+		s.jsonDecoder = d
+		return nil
+	}
+}
+
+func (s *Service) decode(r io.Reader, v interface{}) error {
+	if s.jsonDecoder == nil {
+		return stdJSON.Decode(r, v)
+	}
+	return s.jsonDecoder.Decode(r, v)
+}
@@ -0,0 +1,110 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import (
+	"errors"
+	"io"
+	"sync/atomic"
+	"time"
+)
+
+// Errors reading a response body past the limits of the service
+var (
+	ErrResponseTooLarge = errors.New("response body too large")
+	ErrSlowResponse     = errors.New("response body read timed out")
+)
+
+// Defaults of WithResponseLimits
+const (
+	defaultMaxResponseSize = 64 << 20
+	defaultReadTimeout     = time.Minute
+)
+
+// WithResponseLimits bounds the size of the API response bodies and the
+// longest pause reading them, so a misbehaving proxy can neither make the
+// service buffer unbounded data nor hang it. Zero values keep the defaults,
+// 64MiB and a minute, negative ones disable the limit.
+func WithResponseLimits(maxSize int64, readTimeout time.Duration) Option {
+	return func(s *Service) error {
+		s.maxResponseSize, s.readTimeout = maxSize, readTimeout
+		return nil
+	}
+}
+
+// limit errors once more than the max response size is read from body
+func (s *Service) limit(body io.ReadCloser) io.ReadCloser {
+	max := s.maxResponseSize
+	if max == 0 {
+		max = defaultMaxResponseSize
+	}
+	if max < 0 {
+		return body
+	}
+	return &limitedBody{body, max}
+}
+
+type limitedBody struct {
+	io.ReadCloser
+	left int64
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if int64(len(p)) > b.left+1 {
+		// A byte more than allowed tells a too large body
+		p = p[:b.left+1]
+	}
+	n, err := b.ReadCloser.Read(p)
+	if int64(n) > b.left {
+		n, b.left = int(b.left), 0
+		return n, ErrResponseTooLarge
+	}
+	b.left -= int64(n)
+	return n, err
+}
+
+// watch closes body once a read of it takes longer than the read timeout,
+// the raw body of a response may be closed while being read
+func (s *Service) watch(body io.ReadCloser) io.ReadCloser {
+	timeout := s.readTimeout
+	if timeout == 0 {
+		timeout = defaultReadTimeout
+	}
+	if timeout < 0 {
+		return body
+	}
+
+	b := &slowBody{ReadCloser: body, timeout: timeout}
+	b.timer = time.AfterFunc(timeout, func() {
+		atomic.StoreInt32(&b.timedOut, 1)
+		body.Close()
+	})
+	b.timer.Stop()
+	return b
+}
+
+type slowBody struct {
+	io.ReadCloser
+	timeout  time.Duration
+	timer    *time.Timer
+	timedOut int32
+}
+
+func (b *slowBody) Read(p []byte) (int, error) {
+	b.timer.Reset(b.timeout)
+	n, err := b.ReadCloser.Read(p)
+	b.timer.Stop()
+	if atomic.LoadInt32(&b.timedOut) == 1 {
+		return n, ErrSlowResponse
+	}
+	return n, err
+}
+
+func (b *slowBody) Close() error {
+	b.timer.Stop()
+	return b.ReadCloser.Close()
+}
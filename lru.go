@@ -0,0 +1,83 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import (
+	"container/list"
+	"sync"
+)
+
+// lru is a map of a bounded size dropping the least recently used entries
+// first, it is safe for concurrent use
+type lru struct {
+	mu    sync.Mutex
+	size  int
+	order *list.List // Most recently used first
+	items map[string]*list.Element
+}
+
+type lruEntry struct {
+	key   string
+	value interface{}
+}
+
+// newLRU returns an lru of size entries, one of size 0 keeps nothing
+func newLRU(size int) *lru {
+	return &lru{size: size, order: list.New(), items: map[string]*list.Element{}}
+}
+
+// Load returns the value of key, if any
+func (c *lru) Load(key string) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(e)
+	return e.Value.(*lruEntry).value, true
+}
+
+// Store sets the value of key
+func (c *lru) Store(key string, value interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.items[key]; ok {
+		e.Value.(*lruEntry).value = value
+		c.order.MoveToFront(e)
+		return
+	}
+	if c.size <= 0 {
+		return
+	}
+	if c.order.Len() >= c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*lruEntry).key)
+	}
+	c.items[key] = c.order.PushFront(&lruEntry{key, value})
+}
+
+// Delete removes key
+func (c *lru) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.items[key]; ok {
+		c.order.Remove(e)
+		delete(c.items, key)
+	}
+}
+
+// Len returns the number of entries
+func (c *lru) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
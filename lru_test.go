@@ -0,0 +1,39 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import "testing"
+
+func TestLRU(t *testing.T) {
+	c := newLRU(2)
+	c.Store("a", 1)
+	c.Store("b", 2)
+	if _, ok := c.Load("a"); !ok {
+		t.Fatal("expect a")
+	}
+
+	// b is the least recently used
+	c.Store("c", 3)
+	if _, ok := c.Load("b"); ok || c.Len() != 2 {
+		t.Errorf("expect b to be dropped, got %d entries", c.Len())
+	}
+	if v, ok := c.Load("a"); !ok || v != 1 {
+		t.Errorf("expect a to be kept, got %v", v)
+	}
+
+	c.Delete("a")
+	if _, ok := c.Load("a"); ok || c.Len() != 1 {
+		t.Error("expect a to be deleted")
+	}
+
+	// Nothing kept
+	c = newLRU(0)
+	c.Store("a", 1)
+	if _, ok := c.Load("a"); ok {
+		t.Error("expect nothing kept")
+	}
+}
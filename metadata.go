@@ -0,0 +1,257 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// How long the metadata fields are cached by default
+const metadataTTL = 5 * time.Minute
+
+// MetadataField is the schema of a structured metadata field.
+type MetadataField struct {
+	ExternalID   string              `json:"external_id"`
+	Label        string              `json:"label"`
+	Type         string              `json:"type"` // string, integer, date, enum or set
+	Mandatory    bool                `json:"mandatory"`
+	DefaultValue interface{}         `json:"default_value,omitempty"`
+	Datasource   *MetadataDatasource `json:"datasource,omitempty"`
+}
+
+// MetadataDatasource lists the values of the enum and set fields.
+type MetadataDatasource struct {
+	Values []MetadataValue `json:"values"`
+}
+
+// MetadataValue is a value of an enum or set field.
+type MetadataValue struct {
+	ExternalID string `json:"external_id"`
+	Value      string `json:"value"`
+	State      string `json:"state,omitempty"` // active or inactive
+}
+
+// MetadataFields lists the structured metadata fields of the cloud
+func (s *Service) MetadataFields(ctx context.Context) ([]MetadataField, error) {
+	r, err := s.newAdminRequest(http.MethodGet, "metadata_fields", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	r.ctx = ctx
+
+	var res struct {
+		MetadataFields []MetadataField `json:"metadata_fields"`
+	}
+	if err := s.call(r, &res); err != nil {
+		return nil, err
+	}
+	return res.MetadataFields, nil
+}
+
+// WithMetadataValidation validates the UploadOptions.Metadata and
+// UpdateOptions.Metadata values against the schemas of the fields before
+// calling the API, returning a *MetadataError: their types, the values of
+// the enum and set fields, and on upload the mandatory fields without a
+// default, unless given an UploadPreset. The fields are cached for ttl,
+// 5 minutes if zero.
+func WithMetadataValidation(ttl time.Duration) Option {
+	return func(s *Service) error {
+		if ttl < 0 {
+			return errors.New("negative metadata fields ttl")
+		}
+		if ttl == 0 {
+			ttl = metadataTTL
+		}
+		s.metadataTTL = ttl
+		return nil
+	}
+}
+
+// ErrInvalidMetadata is matched by a *MetadataError with errors.Is
+var ErrInvalidMetadata = errors.New("invalid metadata")
+
+// MetadataError lists the metadata values refused by their field schemas.
+type MetadataError struct {
+	Fields []MetadataFieldError // By external ID
+}
+
+// MetadataFieldError is a metadata value refused by its field schema.
+type MetadataFieldError struct {
+	Field  string      // External ID
+	Value  interface{} // Nil for the missing mandatory fields
+	Reason string
+}
+
+func (e *MetadataError) Error() string {
+	msgs := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		msgs[i] = f.Field + ": " + f.Reason
+	}
+	return "invalid metadata: " + strings.Join(msgs, ", ")
+}
+
+// Is reports whether target is ErrInvalidMetadata
+func (e *MetadataError) Is(target error) bool {
+	return target == ErrInvalidMetadata
+}
+
+// validateMetadata checks values against the cached field schemas, the
+// mandatory fields without default are required on upload only
+func (s *Service) validateMetadata(ctx context.Context, values map[string]interface{}, upload bool) error {
+	if s.metadataTTL <= 0 || (len(values) == 0 && !upload) {
+		return nil
+	}
+	fields, err := s.metadataFields(ctx)
+	if err != nil {
+		return err
+	}
+
+	e := &MetadataError{}
+	for id, value := range values {
+		f, ok := fields[id]
+		if !ok {
+			e.Fields = append(e.Fields, MetadataFieldError{Field: id, Value: value, Reason: "unknown field"})
+		} else if reason := f.check(value); reason != "" {
+			e.Fields = append(e.Fields, MetadataFieldError{Field: id, Value: value, Reason: reason})
+		}
+	}
+	if upload {
+		for id, f := range fields {
+			if _, ok := values[id]; !ok && f.Mandatory && f.DefaultValue == nil {
+				e.Fields = append(e.Fields, MetadataFieldError{Field: id, Reason: "mandatory field missing"})
+			}
+		}
+	}
+
+	if len(e.Fields) == 0 {
+		return nil
+	}
+	sort.Slice(e.Fields, func(i, j int) bool { return e.Fields[i].Field < e.Fields[j].Field })
+	return e
+}
+
+// metadataFields returns the field schemas by external ID, fetched again
+// once they expire
+func (s *Service) metadataFields(ctx context.Context) (map[string]MetadataField, error) {
+	s.state.mu.Lock()
+	fields, fetched := s.state.metadataFields, s.state.metadataFetched
+	s.state.mu.Unlock()
+	if fields != nil && time.Since(fetched) < s.metadataTTL {
+		return fields, nil
+	}
+
+	list, err := s.MetadataFields(ctx)
+	if err != nil {
+		return nil, err
+	}
+	fields = make(map[string]MetadataField, len(list))
+	for _, f := range list {
+		fields[f.ExternalID] = f
+	}
+
+	s.state.mu.Lock()
+	s.state.metadataFields, s.state.metadataFetched = fields, time.Now()
+	s.state.mu.Unlock()
+	return fields, nil
+}
+
+// check returns why value is refused by the field, empty if accepted
+func (f *MetadataField) check(value interface{}) string {
+	switch f.Type {
+	case "string":
+		if _, ok := value.(string); !ok {
+			return "expect a string"
+		}
+	case "integer":
+		if !isInteger(value) {
+			return "expect an integer"
+		}
+	case "date":
+		v, ok := value.(string)
+		if !ok {
+			return "expect a date as YYYY-MM-DD"
+		}
+		if _, err := time.Parse("2006-01-02", v); err != nil {
+			return "expect a date as YYYY-MM-DD"
+		}
+	case "enum":
+		v, ok := value.(string)
+		if !ok {
+			return "expect a string"
+		}
+		if !f.accepts(v) {
+			return fmt.Sprintf("unknown value %q", v)
+		}
+	case "set":
+		values, ok := stringList(value)
+		if !ok {
+			return "expect a list of strings"
+		}
+		for _, v := range values {
+			if !f.accepts(v) {
+				return fmt.Sprintf("unknown value %q", v)
+			}
+		}
+	}
+	return ""
+}
+
+// accepts tells whether the external ID of an active datasource value is v
+func (f *MetadataField) accepts(v string) bool {
+	if f.Datasource == nil {
+		return false
+	}
+	for _, dv := range f.Datasource.Values {
+		if dv.ExternalID == v && dv.State != "inactive" {
+			return true
+		}
+	}
+	return false
+}
+
+func isInteger(value interface{}) bool {
+	switch v := value.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return true
+	case float64:
+		return v == math.Trunc(v)
+	case json.Number:
+		_, err := v.Int64()
+		return err == nil
+	case string:
+		_, err := strconv.ParseInt(v, 10, 64)
+		return err == nil
+	}
+	return false
+}
+
+func stringList(value interface{}) ([]string, bool) {
+	switch v := value.(type) {
+	case []string:
+		return v, true
+	case []interface{}:
+		list := make([]string, len(v))
+		for i, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, false
+			}
+			list[i] = s
+		}
+		return list, true
+	}
+	return nil, false
+}
@@ -0,0 +1,220 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import (
+	"context"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Notifications older than that are refused, as replayed
+const maxNotificationAge = 2 * time.Hour
+
+// Notifications are small JSON documents
+const maxNotificationSize = 1 << 20
+
+// Notification is a webhook notification of Cloudinary, e.g. sent to the
+// NotificationURL of an upload once it is done.
+type Notification struct {
+	NotificationType string        `json:"notification_type"` // e.g. upload or eager
+	PublicID         string        `json:"public_id"`
+	BatchID          string        `json:"batch_id,omitempty"`
+	Version          uint          `json:"version,omitempty"`
+	ResourceType     string        `json:"resource_type,omitempty"`
+	Type             string        `json:"type,omitempty"`
+	Format           string        `json:"format,omitempty"`
+	SecureURL        string        `json:"secure_url,omitempty"`
+	Eager            []EagerResult `json:"eager,omitempty"`
+
+	// Raw is the whole notification, for reading fields not decoded above
+	Raw json.RawMessage `json:"-"`
+}
+
+// VerifyNotification checks the signature of a notification body against
+// the X-Cld-Signature and X-Cld-Timestamp headers it was sent with
+func (s *Service) VerifyNotification(body []byte, header http.Header) error {
+	if s.apiSecret == "" {
+		return errors.New("no API secret to verify with")
+	}
+
+	timestamp := header.Get("X-Cld-Timestamp")
+	sec, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if age := s.signingTime().Sub(time.Unix(sec, 0)); age > maxNotificationAge || age < -maxNotificationAge {
+		return errors.New("stale notification")
+	}
+
+	h := sha1.New()
+	h.Write(body)
+	h.Write([]byte(timestamp + s.apiSecret))
+	expected := hex.EncodeToString(h.Sum(nil))
+	if subtle.ConstantTimeCompare([]byte(expected), []byte(header.Get("X-Cld-Signature"))) != 1 {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// NotificationHandler receives the notifications of a Service, handing
+// them to whoever expects them, e.g. to wait for an async upload.
+// It is safe for concurrent use.
+type NotificationHandler struct {
+	// Retain keeps the notifications nobody expects yet for the
+	// expectations registered late, 10 minutes by default
+	Retain time.Duration
+	// OnNotification is called with every verified notification
+	OnNotification func(*Notification)
+
+	s       *Service
+	mu      sync.Mutex
+	waiting map[string][]*Expectation
+	early   map[string]retainedNotification
+}
+
+type retainedNotification struct {
+	n  *Notification
+	at time.Time
+}
+
+// NewNotificationHandler returns a handler verifying the notifications
+// with the secret of s
+func NewNotificationHandler(s *Service) *NotificationHandler {
+	return &NotificationHandler{
+		s:       s,
+		waiting: map[string][]*Expectation{},
+		early:   map[string]retainedNotification{},
+	}
+}
+
+// ServeHTTP verifies the notification and hands it to its expectations
+func (h *NotificationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxNotificationSize))
+	if err != nil {
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+		return
+	}
+	if err := h.s.VerifyNotification(body, r.Header); err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	n := &Notification{}
+	if err := json.Unmarshal(body, n); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	n.Raw = body
+
+	if h.OnNotification != nil {
+		h.OnNotification(n)
+	}
+	h.dispatch(n)
+}
+
+// dispatch resolves the expectations of the batch ID and the public ID
+// of n, retaining it for the ones not registered yet
+func (h *NotificationHandler) dispatch(n *Notification) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := time.Now()
+	for key, r := range h.early {
+		if now.Sub(r.at) > h.retain() {
+			delete(h.early, key)
+		}
+	}
+
+	for _, key := range []string{n.BatchID, n.PublicID} {
+		if key == "" {
+			continue
+		}
+		if exps := h.waiting[key]; len(exps) > 0 {
+			for _, e := range exps {
+				e.c <- n
+			}
+			delete(h.waiting, key)
+			continue
+		}
+		h.early[key] = retainedNotification{n, now}
+	}
+}
+
+func (h *NotificationHandler) retain() time.Duration {
+	if h.Retain > 0 {
+		return h.Retain
+	}
+	return 10 * time.Minute
+}
+
+// Expect registers an expectation of the notification of key, a batch ID
+// or a public ID, e.g. of the Response of an async upload
+func (h *NotificationHandler) Expect(key string) *Expectation {
+	e := &Expectation{key: key, h: h, c: make(chan *Notification, 1)}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if r, ok := h.early[key]; ok && time.Since(r.at) <= h.retain() {
+		delete(h.early, key)
+		e.c <- r.n
+		return e
+	}
+	h.waiting[key] = append(h.waiting[key], e)
+	return e
+}
+
+// Expectation is a notification expected by a NotificationHandler.
+type Expectation struct {
+	key string
+	h   *NotificationHandler
+	c   chan *Notification
+}
+
+// Wait returns the expected notification, or the error of ctx once done
+func (e *Expectation) Wait(ctx context.Context) (*Notification, error) {
+	select {
+	case n := <-e.c:
+		return n, nil
+	case <-ctx.Done():
+		e.Cancel()
+		return nil, ctx.Err()
+	}
+}
+
+// Cancel drops the expectation
+func (e *Expectation) Cancel() {
+	e.h.mu.Lock()
+	defer e.h.mu.Unlock()
+
+	exps := e.h.waiting[e.key]
+	for i, other := range exps {
+		if other == e {
+			exps = append(exps[:i], exps[i+1:]...)
+			break
+		}
+	}
+	if len(exps) == 0 {
+		delete(e.h.waiting, e.key)
+	} else {
+		e.h.waiting[e.key] = exps
+	}
+}
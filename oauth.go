@@ -0,0 +1,35 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import "errors"
+
+// TokenSource supplies OAuth access tokens. Token is called for every request,
+// so implementations may cache and refresh tokens as they expire.
+type TokenSource interface {
+	Token() (string, error)
+}
+
+// StaticToken is a TokenSource that always returns the same token.
+type StaticToken string
+
+// Token returns the token itself.
+func (t StaticToken) Token() (string, error) {
+	return string(t), nil
+}
+
+// WithTokenSource authenticates requests with an `Authorization: Bearer` OAuth
+// token instead of signing them with the API secret.
+func WithTokenSource(ts TokenSource) Option {
+	return func(s *Service) error {
+		if ts == nil {
+			return errors.New("nil token source")
+		}
+		s.tokenSource = ts
+		return nil
+	}
+}
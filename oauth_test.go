@@ -0,0 +1,43 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWithTokenSource(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer token" {
+			t.Errorf("expect bearer token, got %q", got)
+		}
+		if r.FormValue("signature") != "" || r.FormValue("api_key") != "" {
+			t.Error("request should not be signed")
+		}
+		if r.FormValue("public_id") != "sample" {
+			t.Errorf("missing public_id, got %q", r.FormValue("public_id"))
+		}
+		w.Write([]byte(`{"result":"ok"}`))
+	}))
+	defer srv.Close()
+
+	if _, err := Dial("cloudinary://cloudname"); err == nil {
+		t.Error("should fail without secret nor token source")
+	}
+
+	s, err := Dial("cloudinary://cloudname", WithTokenSource(StaticToken("token")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.apiURL = srv.URL
+
+	if err := s.UploadDestroy("sample", "image"); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,355 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrQueued is returned by the Outbox when an upload failed transiently
+// and was persisted to be retried later.
+var ErrQueued = errors.New("upload queued for retry")
+
+// ErrOutboxClosed is returned by Flush once the Outbox is closed.
+var ErrOutboxClosed = errors.New("outbox closed")
+
+// UploadJob is an upload persisted by the Outbox.
+// Either Data or URL holds the file to upload.
+type UploadJob struct {
+	ID           string    `json:"id"`
+	ResourceType string    `json:"resource_type"`
+	Data         []byte    `json:"data,omitempty"`
+	URL          string    `json:"url,omitempty"`
+	Attempts     int       `json:"attempts"`
+	LastError    string    `json:"last_error,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// Queue durably stores the uploads waiting to be retried.
+// Implementations must be safe for concurrent use.
+type Queue interface {
+	// Push stores a new job or replaces the job with the same ID
+	Push(job *UploadJob) error
+	// Pending returns the stored jobs, oldest first
+	Pending() ([]*UploadJob, error)
+	// Remove deletes a job once it is done with
+	Remove(id string) error
+}
+
+// Outbox uploads through a Service, persisting transiently failed uploads
+// to a Queue so they are retried by a background worker instead of being lost.
+type Outbox struct {
+	// MaxAttempts drops a job after that many failed retries, zero retries forever
+	MaxAttempts int
+	// OnDone is called when a queued job is finally uploaded
+	OnDone func(job *UploadJob, resp *Response)
+	// OnDrop is called when a queued job is given up on
+	OnDrop func(job *UploadJob, err error)
+
+	s *Service
+	q Queue
+
+	mu      sync.Mutex
+	closed  bool
+	stop    chan struct{}
+	wg      sync.WaitGroup // In-flight uploads and the worker
+	workErr error
+}
+
+// NewOutbox returns an Outbox uploading through s and persisting to q
+func NewOutbox(s *Service, q Queue) *Outbox {
+	return &Outbox{s: s, q: q, stop: make(chan struct{})}
+}
+
+// Start runs the worker in the background, see Run and Wait
+func (o *Outbox) Start(ctx context.Context, interval time.Duration) {
+	if !o.acquire() {
+		return
+	}
+	go func() {
+		defer o.wg.Done()
+		if err := o.Run(ctx, interval); err != nil && ctx.Err() == nil {
+			o.mu.Lock()
+			o.workErr = err
+			o.mu.Unlock()
+		}
+	}()
+}
+
+// Close stops the worker once its current job is done. The uploads made
+// afterwards are queued without being tried, for them not to be lost.
+func (o *Outbox) Close() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if !o.closed {
+		o.closed = true
+		close(o.stop)
+	}
+	return nil
+}
+
+// Wait waits for the in-flight uploads and the worker to be done,
+// returning the error the worker stopped on, if any
+func (o *Outbox) Wait() error {
+	o.wg.Wait()
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.workErr
+}
+
+// acquire counts an in-flight operation, unless closed
+func (o *Outbox) acquire() bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.closed {
+		return false
+	}
+	o.wg.Add(1)
+	return true
+}
+
+// UploadByIOReader uploads the content of reader, or queues it when the
+// upload fails transiently, in which case ErrQueued is returned.
+// The content is buffered in memory so it can be persisted.
+func (o *Outbox) UploadByIOReader(reader io.Reader, resourceType string) (*Response, error) {
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	return o.upload(&UploadJob{ResourceType: resourceType, Data: data})
+}
+
+// UploadByURL uploads the file at addr, or queues it when the upload fails
+// transiently, in which case ErrQueued is returned.
+func (o *Outbox) UploadByURL(addr, resourceType string) (*Response, error) {
+	return o.upload(&UploadJob{ResourceType: resourceType, URL: addr})
+}
+
+func (o *Outbox) upload(job *UploadJob) (*Response, error) {
+	// The ID is the idempotency key of every attempt
+	id, err := randomID()
+	if err != nil {
+		return nil, err
+	}
+	job.ID = id
+
+	sendErr := ErrOutboxClosed
+	if o.acquire() {
+		var resp *Response
+		resp, sendErr = o.send(context.Background(), job)
+		o.wg.Done()
+		if sendErr == nil || !transient(sendErr) {
+			return resp, sendErr
+		}
+	}
+
+	job.CreatedAt = time.Now()
+	job.LastError = sendErr.Error()
+	if err := o.q.Push(job); err != nil {
+		return nil, err
+	}
+
+	return nil, ErrQueued
+}
+
+func (o *Outbox) send(ctx context.Context, job *UploadJob) (*Response, error) {
+	opts := &UploadOptions{ResourceType: job.ResourceType, IdempotencyKey: job.ID}
+	return o.s.track(ctx, opts, func() (*Response, error) {
+		if job.URL != "" {
+			return o.s.uploadURL(ctx, job.URL, opts)
+		}
+		return o.s.uploadReader(ctx, bytes.NewReader(job.Data), opts)
+	})
+}
+
+// Flush retries every pending job once, it stops early when ctx is done,
+// when the Outbox is closed or when the queue can't be read.
+func (o *Outbox) Flush(ctx context.Context) error {
+	if !o.acquire() {
+		return ErrOutboxClosed
+	}
+	defer o.wg.Done()
+
+	jobs, err := o.q.Pending()
+	if err != nil {
+		return err
+	}
+
+	for _, job := range jobs {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		select {
+		case <-o.stop:
+			return nil
+		default:
+		}
+
+		resp, err := o.send(ctx, job)
+		if err == nil {
+			if err := o.q.Remove(job.ID); err != nil {
+				return err
+			}
+			if o.OnDone != nil {
+				o.OnDone(job, resp)
+			}
+			continue
+		}
+
+		job.Attempts++
+		job.LastError = err.Error()
+		if !transient(err) || (o.MaxAttempts > 0 && job.Attempts >= o.MaxAttempts) {
+			if err := o.q.Remove(job.ID); err != nil {
+				return err
+			}
+			if o.OnDrop != nil {
+				o.OnDrop(job, err)
+			}
+			continue
+		}
+
+		if err := o.q.Push(job); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Run flushes the queue every interval until ctx is done or the Outbox
+// is closed, in which case it returns nil
+func (o *Outbox) Run(ctx context.Context, interval time.Duration) error {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		err := o.Flush(ctx)
+		if err == ErrOutboxClosed {
+			return nil
+		}
+		if err != nil && ctx.Err() == nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-o.stop:
+			return nil
+		case <-t.C:
+		}
+	}
+}
+
+// FileQueue is a Queue storing each job as a JSON file in a directory.
+type FileQueue struct {
+	mu  sync.Mutex
+	dir string
+}
+
+// NewFileQueue returns a queue stored in dir, creating it if needed
+func NewFileQueue(dir string) (*FileQueue, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	return &FileQueue{dir: dir}, nil
+}
+
+// Push writes the job file atomically
+func (q *FileQueue) Push(job *UploadJob) error {
+	if job.ID == "" || strings.ContainsAny(job.ID, `/\.`) {
+		return errors.New("invalid job ID")
+	}
+
+	b, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	tmp, err := ioutil.TempFile(q.dir, ".job-")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	return os.Rename(tmp.Name(), q.path(job.ID))
+}
+
+// Pending reads all the job files
+func (q *FileQueue) Pending() ([]*UploadJob, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	paths, err := filepath.Glob(filepath.Join(q.dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := make([]*UploadJob, 0, len(paths))
+	for _, path := range paths {
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		job := &UploadJob{}
+		if err := json.Unmarshal(b, job); err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].CreatedAt.Before(jobs[j].CreatedAt)
+	})
+	return jobs, nil
+}
+
+// Remove deletes the job file
+func (q *FileQueue) Remove(id string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	err := os.Remove(q.path(id))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+func (q *FileQueue) path(id string) string {
+	return filepath.Join(q.dir, id+".json")
+}
@@ -0,0 +1,81 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// PageOptions shape the rendering of the pages of a document, e.g. a PDF.
+type PageOptions struct {
+	// Density in dots per inch, 150 by default
+	Density int
+	// Format of the rendering, jpg by default
+	Format         string
+	Transformation string // Applied after the page selection, e.g. c_scale,w_480
+}
+
+// PageTransformation returns the transformation rendering the given pages,
+// either a single page e.g. "2", a range e.g. "2-4" or a list e.g. "1;3"
+func PageTransformation(pages string, p *PageOptions) string {
+	if p == nil {
+		p = &PageOptions{}
+	}
+
+	params := []string{"pg_" + pages}
+	if p.Density > 0 {
+		params = append(params, "dn_"+strconv.Itoa(p.Density))
+	}
+
+	t := strings.Join(params, ",")
+	if p.Transformation != "" {
+		t += "/" + strings.Trim(p.Transformation, "/")
+	}
+	return t
+}
+
+// PageURL returns the URL of a page of a document, from 1
+func (s *Service) PageURL(publicID string, page int, p *PageOptions) string {
+	return s.pagesURL(publicID, strconv.Itoa(page), p)
+}
+
+// PageRangeURL returns the URL of the pages from first to last of a
+// document, rendered e.g. as an animated gif or a multi-page tiff
+func (s *Service) PageRangeURL(publicID string, first, last int, p *PageOptions) string {
+	return s.pagesURL(publicID, strconv.Itoa(first)+"-"+strconv.Itoa(last), p)
+}
+
+func (s *Service) pagesURL(publicID, pages string, p *PageOptions) string {
+	format := "jpg"
+	if p != nil && p.Format != "" {
+		format = p.Format
+	}
+	return s.URL(publicID, &URLOptions{Transformation: PageTransformation(pages, p), Format: format})
+}
+
+// PageCount returns the number of pages of an upload type document
+func (s *Service) PageCount(ctx context.Context, publicID string) (int, error) {
+	r, err := s.newAdminRequest(http.MethodGet, "resources/image/upload/"+escapePublicID(s.prefixed(publicID)), url.Values{"pages": {"true"}}, nil)
+	if err != nil {
+		return 0, err
+	}
+	r.ctx = ctx
+
+	var res Resource
+	if err := s.call(r, &res); err != nil {
+		return 0, err
+	}
+	if res.Pages == 0 {
+		// Single page documents and images
+		return 1, nil
+	}
+	return res.Pages, nil
+}
@@ -0,0 +1,109 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// AssetManifest maps the paths of published files to their versioned
+// delivery URLs, the version busting the caches on every change.
+type AssetManifest map[string]string
+
+// assetTypes are the resource types of the file extensions, raw otherwise
+var assetTypes = map[string]string{
+	".avif": "image", ".gif": "image", ".ico": "image", ".jpeg": "image", ".jpg": "image",
+	".png": "image", ".svg": "image", ".webp": "image",
+	".mov": "video", ".mp3": "video", ".mp4": "video", ".ogg": "video", ".webm": "video",
+}
+
+// PublishAssets uploads every file of fsys, e.g. a build directory, under
+// the remotePrefix folder and returns their manifest. Files keep their
+// path as public ID, without the extension for images and videos.
+func (s *Service) PublishAssets(ctx context.Context, fsys fs.FS, remotePrefix string) (AssetManifest, error) {
+	folder := strings.Trim(remotePrefix, "/")
+	if folder != "" {
+		folder += "/"
+	}
+
+	overwrite, unique := true, false
+	m := AssetManifest{}
+	err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		ext := path.Ext(p)
+		rt, ok := assetTypes[strings.ToLower(ext)]
+		publicID := folder + strings.TrimSuffix(p, ext)
+		if !ok {
+			rt, publicID = "raw", folder+p
+		}
+
+		resp, err := s.Upload(ctx, BodyFunc(func() (io.ReadCloser, error) {
+			return fsys.Open(p)
+		}), &UploadOptions{
+			ResourceType:   rt,
+			PublicID:       publicID,
+			Overwrite:      &overwrite,
+			UniqueFilename: &unique,
+			Invalidate:     true,
+		})
+		if err != nil {
+			return fmt.Errorf("%s: %w", p, err)
+		}
+
+		opts := &URLOptions{ResourceType: rt, Version: resp.Version}
+		if rt != "raw" {
+			opts.Format = resp.Format
+		}
+		m[p] = s.URL(publicID, opts)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ReadAssetManifest decodes a manifest written by WriteJSON
+func ReadAssetManifest(r io.Reader) (AssetManifest, error) {
+	m := AssetManifest{}
+	if err := json.NewDecoder(r).Decode(&m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// WriteJSON writes the manifest as a JSON object
+func (m AssetManifest) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(m)
+}
+
+// URL returns the delivery URL of the file at path p
+func (m AssetManifest) URL(p string) (string, error) {
+	u, ok := m[strings.TrimPrefix(p, "/")]
+	if !ok {
+		return "", fmt.Errorf("asset %q not in the manifest", p)
+	}
+	return u, nil
+}
+
+// FuncMap returns the asset template function resolving the paths,
+// e.g. <img src="{{asset "img/logo.png"}}">
+func (m AssetManifest) FuncMap() template.FuncMap {
+	return template.FuncMap{"asset": m.URL}
+}
@@ -0,0 +1,60 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+// PlaceholderTransformation is a tiny blurred version of an image,
+// for the blur-up loading pattern
+const PlaceholderTransformation = "c_scale,w_32/e_blur:1000,q_1"
+
+// Placeholders larger than this are not inlined
+const maxPlaceholderSize = 64 << 10
+
+// PlaceholderURL returns the URL of the low quality placeholder of an image
+func (s *Service) PlaceholderURL(publicID string) string {
+	return s.URL(publicID, &URLOptions{Transformation: PlaceholderTransformation, Format: "jpg", NoDefault: true})
+}
+
+// PlaceholderDataURI fetches the placeholder of an image as a base64 data
+// URI, to be inlined in the pages
+func (s *Service) PlaceholderDataURI(ctx context.Context, publicID string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.PlaceholderURL(publicID), nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", newAPIError(resp)
+	}
+
+	b, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxPlaceholderSize+1))
+	if err != nil {
+		return "", err
+	}
+	if len(b) > maxPlaceholderSize {
+		return "", fmt.Errorf("placeholder of %s is larger than %d bytes", publicID, maxPlaceholderSize)
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = "image/jpeg"
+	}
+	return "data:" + contentType + ";base64," + base64.StdEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,87 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import (
+	"net/url"
+	"strings"
+)
+
+const playerBaseURL = "https://player.cloudinary.com/embed/"
+
+// PlayerOptions configure the hosted Video Player, zero values are left out.
+// The player builds the delivery URLs itself, in the browser, out of the
+// public ID and the transformation: those URLs can't carry a signature of
+// the service. The assets must then be of the upload type and, with strict
+// transformations, the transformation allowed by name.
+type PlayerOptions struct {
+	// Transformation is applied to the video, e.g. c_fill,w_640,
+	// along with the one of WithDefaultTransformation unless NoDefault
+	Transformation string
+	NoDefault      bool
+	Profile        string // Player profile, e.g. cld-default
+	Autoplay       bool
+	Muted          bool // Needed by most browsers to autoplay
+	Loop           bool
+	HideControls   bool
+	Fluid          bool // Resizes with the container
+	// Analytics sends the player events to the Google Analytics of the page
+	Analytics bool
+	// Extra parameters are added verbatim, e.g. player[colors][accent]
+	Extra map[string]string
+}
+
+// PlayerURL returns the URL of the hosted Video Player embedding a video,
+// to be the source of an iframe, opts may be nil. It is not signed, see
+// PlayerOptions.
+func (s *Service) PlayerURL(publicID string, opts *PlayerOptions) string {
+	if opts == nil {
+		opts = &PlayerOptions{}
+	}
+
+	q := url.Values{}
+	q.Set("cloud_name", s.cloudName)
+	q.Set("public_id", s.prefixed(publicID))
+	if opts.Profile != "" {
+		q.Set("profile", opts.Profile)
+	}
+
+	var chain []string
+	if opts.Transformation != "" {
+		chain = append(chain, strings.Trim(opts.Transformation, "/"))
+	}
+	if s.urlTransformation != "" && !opts.NoDefault {
+		chain = append(chain, s.urlTransformation)
+	}
+	if len(chain) > 0 {
+		q.Set("source[transformation][raw_transformation]", strings.Join(chain, "/"))
+	}
+
+	flags := []struct {
+		name string
+		set  bool
+	}{
+		{"autoplay", opts.Autoplay},
+		{"muted", opts.Muted},
+		{"loop", opts.Loop},
+		{"fluid", opts.Fluid},
+		{"analytics", opts.Analytics},
+	}
+	for _, f := range flags {
+		if f.set {
+			q.Set("player["+f.name+"]", "true")
+		}
+	}
+	if opts.HideControls {
+		q.Set("player[controls]", "false")
+	}
+	for key, value := range opts.Extra {
+		q.Set(key, value)
+	}
+
+	return playerBaseURL + "?" + q.Encode()
+}
@@ -0,0 +1,56 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"sync"
+)
+
+// Buffers larger than this are left to the GC rather than pooled,
+// so a single large upload doesn't pin memory forever
+const maxPooledBuffer = 4 << 20
+
+var (
+	// Buffers holding the content of unseekable readers
+	bufferPool = sync.Pool{New: func() interface{} { return new(bytes.Buffer) }}
+	// Buffers for copying files into multipart bodies
+	copyPool = sync.Pool{New: func() interface{} { b := make([]byte, 32<<10); return &b }}
+	// Writers batching the small multipart writes into the body pipe
+	writerPool = sync.Pool{New: func() interface{} { return bufio.NewWriterSize(nil, 4<<10) }}
+)
+
+func getBuffer() *bytes.Buffer {
+	return bufferPool.Get().(*bytes.Buffer)
+}
+
+func putBuffer(b *bytes.Buffer) {
+	if b.Cap() > maxPooledBuffer {
+		return
+	}
+	b.Reset()
+	bufferPool.Put(b)
+}
+
+func copyBuffer(dst io.Writer, src io.Reader) (int64, error) {
+	b := copyPool.Get().(*[]byte)
+	defer copyPool.Put(b)
+	return io.CopyBuffer(dst, src, *b)
+}
+
+func getWriter(w io.Writer) *bufio.Writer {
+	bw := writerPool.Get().(*bufio.Writer)
+	bw.Reset(w)
+	return bw
+}
+
+func putWriter(bw *bufio.Writer) {
+	bw.Reset(nil)
+	writerPool.Put(bw)
+}
@@ -0,0 +1,65 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import (
+	"errors"
+	"strings"
+)
+
+// WithFolderPrefix namespaces the assets of the Service under a folder,
+// e.g. staging/, to isolate environments sharing one cloud. The prefix is
+// added to the public IDs given to uploads, destroys, deletions, renames,
+// updates and URLs unless they already start with it, so the public IDs
+// returned by the API can be used as is.
+func WithFolderPrefix(prefix string) Option {
+	return func(s *Service) error {
+		prefix = strings.Trim(prefix, "/")
+		if prefix == "" {
+			return errors.New("empty folder prefix")
+		}
+		if err := ValidatePublicID(prefix); err != nil {
+			return err
+		}
+		s.folderPrefix = prefix + "/"
+		return nil
+	}
+}
+
+// prefixed returns publicID under the folder prefix
+func (s *Service) prefixed(publicID string) string {
+	if s.folderPrefix == "" || publicID == "" || strings.HasPrefix(publicID+"/", s.folderPrefix) {
+		return publicID
+	}
+	return s.folderPrefix + publicID
+}
+
+// prefixedPrefix returns a public ID prefix under the folder prefix, by
+// the rule of prefixed. The empty prefix and the bare folder name stand
+// for the whole folder, for nothing beyond it to match.
+func (s *Service) prefixedPrefix(prefix string) string {
+	if prefix == "" || prefix+"/" == s.folderPrefix {
+		return s.folderPrefix
+	}
+	return s.prefixed(prefix)
+}
+
+// prefixUpload puts the uploaded file under the folder prefix,
+// through its folder or else its public ID
+func (s *Service) prefixUpload(params map[string]string) {
+	if s.folderPrefix == "" {
+		return
+	}
+	switch {
+	case params["folder"] != "":
+		params["folder"] = s.prefixed(params["folder"])
+	case params["public_id"] != "":
+		params["public_id"] = s.prefixed(params["public_id"])
+	default:
+		params["folder"] = strings.TrimSuffix(s.folderPrefix, "/")
+	}
+}
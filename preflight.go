@@ -0,0 +1,119 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// Reasons of a *PreflightError, matched with errors.Is
+var (
+	ErrRemoteUnreachable = errors.New("remote file unreachable")
+	ErrRemoteContentType = errors.New("unexpected remote file content type")
+	ErrRemoteTooLarge    = errors.New("remote file too large")
+)
+
+// Preflight checks a remote file before Cloudinary is asked to fetch it,
+// failing fast with a typed error rather than an opaque 400 from the API.
+// The HEAD request is sent through the HTTP client of the service. The
+// servers answering it with 405 Method Not Allowed or 501 Not Implemented
+// are not checked.
+type Preflight struct {
+	// ContentTypes are the accepted media types, or their prefixes such as
+	// image/, any type is accepted when empty
+	ContentTypes []string
+	// MaxSize is the largest accepted file, in bytes, zero is unlimited.
+	// Files of an unknown size are accepted.
+	MaxSize int64
+}
+
+// PreflightError is returned when a remote file fails its Preflight check.
+type PreflightError struct {
+	URL         string
+	StatusCode  int    // Of the HEAD request, zero if it failed
+	ContentType string // Media type of the file, if known
+	Size        int64  // Size of the file, -1 if unknown
+	// Reason is ErrRemoteUnreachable, ErrRemoteContentType or ErrRemoteTooLarge
+	Reason error
+	Err    error // Failure of the HEAD request, if any
+}
+
+func (e *PreflightError) Error() string {
+	msg := e.Reason.Error() + ": " + e.URL
+	switch {
+	case e.Err != nil:
+		msg += ": " + e.Err.Error()
+	case e.Reason == ErrRemoteUnreachable:
+		msg += fmt.Sprintf(": status %d", e.StatusCode)
+	case e.Reason == ErrRemoteContentType:
+		msg += ": " + e.ContentType
+	case e.Reason == ErrRemoteTooLarge:
+		msg += fmt.Sprintf(": %d bytes", e.Size)
+	}
+	return msg
+}
+
+// Is reports whether target is the Reason of the error
+func (e *PreflightError) Is(target error) bool {
+	return target == e.Reason
+}
+
+// Unwrap returns the failure of the HEAD request, if any
+func (e *PreflightError) Unwrap() error {
+	return e.Err
+}
+
+// check sends a HEAD request for addr through client, any file other than
+// http(s) is left to Cloudinary
+func (p *Preflight) check(ctx context.Context, client *http.Client, addr string) error {
+	if !strings.HasPrefix(addr, "http://") && !strings.HasPrefix(addr, "https://") {
+		return nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, addr, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return &PreflightError{URL: addr, Size: -1, Reason: ErrRemoteUnreachable, Err: err}
+	}
+	resp.Body.Close()
+
+	e := &PreflightError{URL: addr, StatusCode: resp.StatusCode, Size: resp.ContentLength}
+	e.ContentType, _, _ = mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	switch {
+	case resp.StatusCode == http.StatusMethodNotAllowed || resp.StatusCode == http.StatusNotImplemented:
+		return nil
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		e.Reason = ErrRemoteUnreachable
+	case len(p.ContentTypes) > 0 && !p.accepts(e.ContentType):
+		e.Reason = ErrRemoteContentType
+	case p.MaxSize > 0 && e.Size > p.MaxSize:
+		e.Reason = ErrRemoteTooLarge
+	default:
+		return nil
+	}
+	return e
+}
+
+func (p *Preflight) accepts(contentType string) bool {
+	for _, t := range p.ContentTypes {
+		if contentType == t || (strings.HasSuffix(t, "/") && strings.HasPrefix(contentType, t)) {
+			return true
+		}
+	}
+	return false
+}
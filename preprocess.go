@@ -0,0 +1,248 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/jpeg"
+	"io"
+	"io/ioutil"
+	"os"
+)
+
+// Preprocessor transforms a local file before it is uploaded,
+// e.g. to strip private metadata.
+type Preprocessor interface {
+	// Process returns the content to upload in place of r,
+	// r stays open until the upload is done
+	Process(r io.Reader) (io.Reader, error)
+}
+
+// PreprocessorFunc adapts a function to a Preprocessor
+type PreprocessorFunc func(r io.Reader) (io.Reader, error)
+
+// Process implements Preprocessor
+func (f PreprocessorFunc) Process(r io.Reader) (io.Reader, error) {
+	return f(r)
+}
+
+// Preprocessors chains several preprocessors, in order
+type Preprocessors []Preprocessor
+
+// Process implements Preprocessor
+func (ps Preprocessors) Process(r io.Reader) (io.Reader, error) {
+	for _, p := range ps {
+		var err error
+		if r, err = p.Process(r); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// StripMetadata is a Preprocessor removing the EXIF (including GPS),
+// XMP and IPTC segments of JPEG files, other files are left untouched.
+type StripMetadata struct {
+	// Orient applies the EXIF orientation to the pixels before it is lost,
+	// re-encoding the rotated or mirrored images
+	Orient bool
+	// Quality of the re-encoded images, defaults to 90
+	Quality int
+}
+
+var errNotJPEG = errors.New("not a JPEG file")
+
+// Process implements Preprocessor
+func (p StripMetadata) Process(r io.Reader) (io.Reader, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	segments, err := jpegSegments(data)
+	if err == errNotJPEG {
+		return bytes.NewReader(data), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if p.Orient {
+		if o := jpegOrientation(segments); o > 1 {
+			img, err := jpeg.Decode(bytes.NewReader(data))
+			if err != nil {
+				return nil, err
+			}
+
+			quality := p.Quality
+			if quality == 0 {
+				quality = 90
+			}
+			var buf bytes.Buffer
+			if err := jpeg.Encode(&buf, orient(img, o), &jpeg.Options{Quality: quality}); err != nil {
+				return nil, err
+			}
+			// The encoder writes no metadata
+			return bytes.NewReader(buf.Bytes()), nil
+		}
+	}
+
+	out := make([]byte, 0, len(data))
+	out = append(out, data[:2]...)
+	for _, seg := range segments {
+		switch seg[1] {
+		case 0xe1, 0xed: // APP1 (EXIF, XMP), APP13 (IPTC)
+			continue
+		}
+		out = append(out, seg...)
+	}
+	return bytes.NewReader(out), nil
+}
+
+// jpegSegments splits a JPEG file after its SOI marker, the last segment
+// holds the start of scan along with the rest of the file
+func jpegSegments(data []byte) ([][]byte, error) {
+	if len(data) < 2 || data[0] != 0xff || data[1] != 0xd8 {
+		return nil, errNotJPEG
+	}
+
+	var segments [][]byte
+	for i := 2; i < len(data); {
+		if data[i] != 0xff || i+1 >= len(data) {
+			return nil, errors.New("invalid JPEG segment")
+		}
+
+		marker := data[i+1]
+		switch {
+		case marker == 0xff: // Fill byte
+			i++
+			continue
+		case marker == 0x01 || (marker >= 0xd0 && marker <= 0xd7): // No length
+			segments = append(segments, data[i:i+2])
+			i += 2
+			continue
+		case marker == 0xda || marker == 0xd9: // Start of scan, end of image
+			return append(segments, data[i:]), nil
+		}
+
+		if i+4 > len(data) {
+			return nil, errors.New("truncated JPEG segment")
+		}
+		end := i + 2 + int(binary.BigEndian.Uint16(data[i+2:]))
+		if end > len(data) {
+			return nil, errors.New("truncated JPEG segment")
+		}
+		segments = append(segments, data[i:end])
+		i = end
+	}
+	return segments, nil
+}
+
+// jpegOrientation returns the EXIF orientation (1 to 8) of the segments,
+// or 0 when unknown
+func jpegOrientation(segments [][]byte) int {
+	for _, seg := range segments {
+		if seg[1] != 0xe1 || len(seg) < 10 || string(seg[4:10]) != "Exif\x00\x00" {
+			continue
+		}
+
+		tiff := seg[10:]
+		if len(tiff) < 8 {
+			return 0
+		}
+		var order binary.ByteOrder
+		switch string(tiff[:2]) {
+		case "II":
+			order = binary.LittleEndian
+		case "MM":
+			order = binary.BigEndian
+		default:
+			return 0
+		}
+
+		ifd := int(order.Uint32(tiff[4:]))
+		if ifd+2 > len(tiff) {
+			return 0
+		}
+		n := int(order.Uint16(tiff[ifd:]))
+		for i := 0; i < n; i++ {
+			entry := ifd + 2 + i*12
+			if entry+12 > len(tiff) {
+				return 0
+			}
+			if order.Uint16(tiff[entry:]) == 0x0112 { // Orientation, a SHORT
+				if o := int(order.Uint16(tiff[entry+8:])); o >= 1 && o <= 8 {
+					return o
+				}
+				return 0
+			}
+		}
+	}
+	return 0
+}
+
+// orient returns img as displayed with the given EXIF orientation
+func orient(img image.Image, o int) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+
+	dw, dh := w, h
+	if o >= 5 {
+		dw, dh = h, w
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		for x := 0; x < dw; x++ {
+			var sx, sy int
+			switch o {
+			case 2:
+				sx, sy = w-1-x, y
+			case 3:
+				sx, sy = w-1-x, h-1-y
+			case 4:
+				sx, sy = x, h-1-y
+			case 5:
+				sx, sy = y, x
+			case 6:
+				sx, sy = y, h-1-x
+			case 7:
+				sx, sy = w-1-y, h-1-x
+			case 8:
+				sx, sy = w-1-y, x
+			default:
+				sx, sy = x, y
+			}
+			dst.Set(x, y, img.At(b.Min.X+sx, b.Min.Y+sy))
+		}
+	}
+	return dst
+}
+
+// openLocal opens the content of a local file given to Upload,
+// it returns nil for remote files
+func openLocal(file interface{}) (io.ReadCloser, error) {
+	switch f := file.(type) {
+	case string:
+		if remoteFile.MatchString(f) {
+			return nil, nil
+		}
+		return os.Open(f)
+	case BodyFunc:
+		return f()
+	case func() (io.ReadCloser, error):
+		return f()
+	case []byte:
+		return ioutil.NopCloser(bytes.NewReader(f)), nil
+	case io.Reader:
+		return ioutil.NopCloser(f), nil
+	}
+	return nil, nil
+}
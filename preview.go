@@ -0,0 +1,66 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PreviewOptions shape the video preview generated by e_preview,
+// zero values leave the defaults of Cloudinary.
+type PreviewOptions struct {
+	Duration       float64 // Of the whole preview, in seconds
+	MaxSegments    int
+	MinSegmentDur  float64 // In seconds
+	Transformation string  // Applied before the preview, e.g. c_scale,w_480
+}
+
+// PreviewTransformation returns the transformation generating a smart
+// preview clip of a video. Previews are long to analyse, request them as
+// an eager async transformation on upload or explicit then build the
+// URL with PreviewURL once generated.
+func PreviewTransformation(p *PreviewOptions) string {
+	if p == nil {
+		p = &PreviewOptions{}
+	}
+
+	float := func(f float64) string {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	effect := "e_preview"
+	if p.Duration > 0 {
+		effect += ":duration_" + float(p.Duration)
+	}
+	if p.MaxSegments > 0 {
+		effect += fmt.Sprintf(":max_seg_%d", p.MaxSegments)
+	}
+	if p.MinSegmentDur > 0 {
+		effect += ":min_seg_dur_" + float(p.MinSegmentDur)
+	}
+
+	if p.Transformation != "" {
+		return strings.TrimSuffix(p.Transformation, "/") + "/" + effect
+	}
+	return effect
+}
+
+// PreviewURL returns the URL of the mp4 preview clip of a video
+func (s *Service) PreviewURL(publicID string, p *PreviewOptions) string {
+	return s.URL(publicID, &URLOptions{
+		ResourceType:   "video",
+		Transformation: PreviewTransformation(p),
+		Format:         "mp4",
+	})
+}
+
+// ChaptersPublicID returns the public ID of the raw vtt file generated by
+// the auto chaptering of a video
+func ChaptersPublicID(videoPublicID string) string {
+	return videoPublicID + "-chapters.vtt"
+}
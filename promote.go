@@ -0,0 +1,193 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Assets copied at once by PromoteByTag, unless set otherwise
+const promoteConcurrency = 4
+
+// PromotionLog remembers the assets promoted already, so an interrupted
+// promotion resumes where it stopped. Implementations must be safe for
+// concurrent use.
+type PromotionLog interface {
+	Promoted(key string) (bool, error)
+	MarkPromoted(key string) error
+}
+
+// PromoteOptions are the optional parameters of PromoteByTag.
+type PromoteOptions struct {
+	Concurrency int // Assets copied at once, 4 by default
+	// Log skips the assets promoted by an earlier run
+	Log PromotionLog
+	// Copy holds the options of each copy, its ResourceType and Type are
+	// those of the assets
+	Copy *CopyOptions
+}
+
+// PromoteReport sums up a promotion, public IDs are those of the source.
+type PromoteReport struct {
+	Promoted []string
+	Skipped  []string // Promoted by an earlier run
+	Failed   map[string]error
+}
+
+// PromoteByTag copies the image, video and raw assets having tag from the
+// source cloud into the target one, e.g. from staging to production, see
+// CopyTo. Failed copies are reported rather than stopping the promotion,
+// unless rate limited: the remaining assets are then given up on. The
+// error is that of the listing of the assets, the report holding the
+// promotion so far. opts may be nil.
+func PromoteByTag(ctx context.Context, source, target *Service, tag string, opts *PromoteOptions) (*PromoteReport, error) {
+	if opts == nil {
+		opts = &PromoteOptions{}
+	}
+	n := opts.Concurrency
+	if n <= 0 {
+		n = promoteConcurrency
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		limitErr error
+	)
+	report := &PromoteReport{Failed: map[string]error{}}
+	promote := func(r Resource) {
+		key := r.ResourceType + "/" + r.Type + "/" + r.PublicID
+		mu.Lock()
+		err := limitErr
+		mu.Unlock()
+
+		var done bool
+		if err == nil && opts.Log != nil {
+			done, err = opts.Log.Promoted(key)
+		}
+		if err == nil && !done {
+			c := CopyOptions{}
+			if opts.Copy != nil {
+				c = *opts.Copy
+			}
+			c.ResourceType, c.Type = r.ResourceType, r.Type
+
+			_, err = source.CopyTo(ctx, target, r.PublicID, &c)
+			if err == nil && opts.Log != nil {
+				err = opts.Log.MarkPromoted(key)
+			}
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case err == nil && done:
+			report.Skipped = append(report.Skipped, r.PublicID)
+		case err == nil:
+			report.Promoted = append(report.Promoted, r.PublicID)
+		default:
+			if Category(err) == CategoryRateLimit && limitErr == nil {
+				limitErr = err
+			}
+			report.Failed[r.PublicID] = err
+		}
+	}
+
+	sem := make(chan struct{}, n)
+	var listErr error
+	for _, rt := range []string{"image", "video", "raw"} {
+		it := source.IterateResourcesByTag(ctx, rt, tag, &ResourcesQuery{MaxResults: 500})
+		for it.Next() {
+			r := *it.Resource()
+			if r.ResourceType == "" {
+				r.ResourceType = rt
+			}
+			if r.Type == "" {
+				r.Type = "upload"
+			}
+
+			wg.Add(1)
+			sem <- struct{}{}
+			go func() {
+				defer func() {
+					<-sem
+					wg.Done()
+				}()
+				promote(r)
+			}()
+		}
+		if listErr = it.Err(); listErr != nil {
+			break
+		}
+	}
+	wg.Wait()
+
+	sort.Strings(report.Promoted)
+	sort.Strings(report.Skipped)
+	return report, listErr
+}
+
+// FilePromotionLog is a PromotionLog appending the promoted assets to a file.
+type FilePromotionLog struct {
+	mu   sync.Mutex
+	f    *os.File
+	done map[string]bool
+}
+
+// OpenFilePromotionLog opens the log at path, creating it if needed
+func OpenFilePromotionLog(path string) (*FilePromotionLog, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &FilePromotionLog{f: f, done: map[string]bool{}}
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		if key := sc.Text(); key != "" {
+			l.done[key] = true
+		}
+	}
+	if err := sc.Err(); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return l, nil
+}
+
+// Promoted tells whether key is in the log
+func (l *FilePromotionLog) Promoted(key string) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.done[key], nil
+}
+
+// MarkPromoted appends key to the log
+func (l *FilePromotionLog) MarkPromoted(key string) error {
+	if strings.ContainsAny(key, "\r\n") {
+		return errors.New("invalid promotion log key")
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, err := l.f.WriteString(key + "\n"); err != nil {
+		return err
+	}
+	l.done[key] = true
+	return nil
+}
+
+// Close closes the file of the log
+func (l *FilePromotionLog) Close() error {
+	return l.f.Close()
+}
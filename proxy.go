@@ -0,0 +1,94 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import (
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+)
+
+// DeliveryHandler serves the assets from the delivery URLs, proxying or
+// redirecting, so the cloud stays off the public hostname.
+// The request path, less its leading slash, is the file name as for FS.
+type DeliveryHandler struct {
+	// Options shape the delivery URLs, e.g. a Transformation
+	Options URLOptions
+	// Map, when set, returns the public ID and the delivery options of
+	// a request, ok false answers Not Found
+	Map func(r *http.Request) (publicID string, opts URLOptions, ok bool)
+	// Redirect answers with a redirection instead of proxying
+	Redirect bool
+	// CacheControl is set on the responses, when not empty
+	CacheControl string
+
+	s     *Service
+	proxy *httputil.ReverseProxy
+}
+
+// NewDeliveryHandler returns a handler delivering the assets of s,
+// proxied and cached for a day by default
+func NewDeliveryHandler(s *Service) *DeliveryHandler {
+	h := &DeliveryHandler{s: s, CacheControl: "public, max-age=86400"}
+	h.proxy = &httputil.ReverseProxy{
+		Director: func(r *http.Request) {
+			// The target was set by ServeHTTP
+			r.Host = r.URL.Host
+			r.Header.Del("Cookie")
+			r.Header.Del("Authorization")
+		},
+		Transport: s.client.Transport,
+		ModifyResponse: func(resp *http.Response) error {
+			if h.CacheControl != "" && resp.StatusCode == http.StatusOK {
+				resp.Header.Set("Cache-Control", h.CacheControl)
+			}
+			resp.Header.Del("Set-Cookie")
+			return nil
+		},
+	}
+	return h
+}
+
+func (h *DeliveryHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	var publicID string
+	var opts URLOptions
+	ok := true
+	if h.Map != nil {
+		publicID, opts, ok = h.Map(r)
+	} else {
+		publicID, opts = assetPath(strings.TrimPrefix(r.URL.Path, "/"), h.Options)
+	}
+	if !ok || ValidatePublicID(publicID) != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	target, err := url.Parse(h.s.URL(publicID, &opts))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if h.Redirect {
+		if h.CacheControl != "" {
+			w.Header().Set("Cache-Control", h.CacheControl)
+		}
+		http.Redirect(w, r, target.String(), http.StatusFound)
+		return
+	}
+
+	r = r.Clone(r.Context())
+	r.URL = target
+	h.proxy.ServeHTTP(w, r)
+}
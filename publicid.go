@@ -0,0 +1,80 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// The characters Cloudinary refuses in public IDs
+const disallowedPublicIDChars = `?&#\%<>`
+
+// ErrInvalidPublicID is matched by a *PublicIDError with errors.Is
+var ErrInvalidPublicID = errors.New("invalid public ID")
+
+// PublicIDError is returned before sending a request with a public ID
+// Cloudinary would refuse.
+type PublicIDError struct {
+	PublicID string
+	Char     rune // The first disallowed character, 0 when empty
+}
+
+func (e *PublicIDError) Error() string {
+	if e.Char == 0 {
+		return "invalid public ID: empty"
+	}
+	return fmt.Sprintf("invalid public ID %q: character %q is not allowed", e.PublicID, e.Char)
+}
+
+// Is reports whether target is ErrInvalidPublicID
+func (e *PublicIDError) Is(target error) bool {
+	return target == ErrInvalidPublicID
+}
+
+// ValidatePublicID returns a *PublicIDError when Cloudinary would refuse
+// the public ID, i.e. when it is empty or holds one of ?&#\%<> or
+// a control character
+func ValidatePublicID(publicID string) error {
+	if strings.Trim(publicID, " /") == "" {
+		return &PublicIDError{PublicID: publicID}
+	}
+	for _, c := range publicID {
+		if strings.ContainsRune(disallowedPublicIDChars, c) || unicode.IsControl(c) {
+			return &PublicIDError{PublicID: publicID, Char: c}
+		}
+	}
+	return nil
+}
+
+// SanitizePublicID replaces the characters Cloudinary refuses in public IDs
+// with underscores and trims the surrounding spaces and slashes
+func SanitizePublicID(s string) string {
+	s = strings.Map(func(c rune) rune {
+		if strings.ContainsRune(disallowedPublicIDChars, c) || unicode.IsControl(c) {
+			return '_'
+		}
+		return c
+	}, s)
+	return strings.Trim(s, " /")
+}
+
+// validatePublicIDs validates the public IDs given to a request,
+// empty ones are left to the API
+func validatePublicIDs(publicIDs ...string) error {
+	for _, id := range publicIDs {
+		if id == "" {
+			continue
+		}
+		if err := ValidatePublicID(id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import (
+	"fmt"
+	"time"
+)
+
+// QuotaThresholds are the used percentages of the plan limits
+// from which CheckQuota warns, a zero threshold is not checked.
+type QuotaThresholds struct {
+	Credits         float64
+	Storage         float64
+	Bandwidth       float64
+	Transformations float64
+}
+
+// QuotaWarning is a plan limit used past its threshold.
+type QuotaWarning struct {
+	Metric      string // credits, storage, bandwidth or transformations
+	Usage       float64
+	Limit       float64
+	UsedPercent float64
+	Threshold   float64
+}
+
+func (w QuotaWarning) String() string {
+	return fmt.Sprintf("%s at %.1f%% of the limit (threshold %.1f%%)", w.Metric, w.UsedPercent, w.Threshold)
+}
+
+// CheckQuota compares the current usage with the thresholds,
+// returning a warning for each exceeded one
+func (s *Service) CheckQuota(thresholds QuotaThresholds) ([]QuotaWarning, error) {
+	u, err := s.Usage(time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	return quotaWarnings(u, thresholds), nil
+}
+
+func quotaWarnings(u *Usage, thresholds QuotaThresholds) []QuotaWarning {
+	var warnings []QuotaWarning
+	check := func(metric string, m UsageMetric, threshold float64) {
+		if threshold <= 0 {
+			return
+		}
+
+		used := m.UsedPercent
+		if used == 0 && m.Limit > 0 {
+			used = m.Usage / m.Limit * 100
+		}
+		if used >= threshold {
+			warnings = append(warnings, QuotaWarning{
+				Metric:      metric,
+				Usage:       m.Usage,
+				Limit:       m.Limit,
+				UsedPercent: used,
+				Threshold:   threshold,
+			})
+		}
+	}
+
+	check("credits", u.Credits, thresholds.Credits)
+	check("storage", u.Storage, thresholds.Storage)
+	check("bandwidth", u.Bandwidth, thresholds.Bandwidth)
+	check("transformations", u.Transformations, thresholds.Transformations)
+	return warnings
+}
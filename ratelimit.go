@@ -0,0 +1,175 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// APIFamily groups the endpoints sharing a rate limit allowance.
+type APIFamily string
+
+// Cloudinary API families
+const (
+	UploadAPI APIFamily = "upload"
+	AdminAPI  APIFamily = "admin"
+)
+
+// ErrRateLimited is returned by a rejecting TokenBucket when no operation is left.
+var ErrRateLimited = errors.New("rate limit reached")
+
+// WithFamilyLimiter throttles the requests of a single API family through l,
+// it takes precedence over WithLimiter.
+func WithFamilyLimiter(family APIFamily, l Limiter) Option {
+	return func(s *Service) error {
+		if s.limiters == nil {
+			s.limiters = map[APIFamily]Limiter{}
+		}
+		s.limiters[family] = l
+		return nil
+	}
+}
+
+// WithRateLimit allows opsPerHour operations of an API family,
+// delaying requests once the allowance is spent.
+func WithRateLimit(family APIFamily, opsPerHour int) Option {
+	return func(s *Service) error {
+		if opsPerHour <= 0 {
+			return errors.New("rate limit must be positive")
+		}
+		return WithFamilyLimiter(family, NewTokenBucket(opsPerHour, false))(s)
+	}
+}
+
+func (s *Service) limiterFor(family APIFamily) Limiter {
+	if l, ok := s.limiters[family]; ok {
+		return l
+	}
+	return s.limiter
+}
+
+// TokenBucket is a Limiter allowing a number of operations per hour,
+// refilled continuously. It is safe for concurrent use.
+//
+// When used by a Service, the bucket is also refreshed from the
+// X-FeatureRateLimit-* headers of the responses, so it follows the
+// allowance actually left on the account.
+type TokenBucket struct {
+	mu       sync.Mutex
+	capacity float64
+	tokens   float64
+	rate     float64 // Tokens per second
+	last     time.Time
+	blocked  time.Time // No token until then, set from headers
+	reject   bool
+	now      func() time.Time
+}
+
+// NewTokenBucket returns a full bucket of opsPerHour operations.
+// Wait delays calls until a token is available, or fails fast with
+// ErrRateLimited when reject is true.
+func NewTokenBucket(opsPerHour int, reject bool) *TokenBucket {
+	return &TokenBucket{
+		capacity: float64(opsPerHour),
+		tokens:   float64(opsPerHour),
+		rate:     float64(opsPerHour) / time.Hour.Seconds(),
+		reject:   reject,
+		now:      time.Now,
+	}
+}
+
+// Wait takes a token from the bucket
+func (b *TokenBucket) Wait(ctx context.Context) error {
+	for {
+		d, err := b.take()
+		if err != nil || d == 0 {
+			return err
+		}
+
+		t := time.NewTimer(d)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// take consumes a token, otherwise it returns how long to wait for one
+func (b *TokenBucket) take() (time.Duration, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	b.refill(now)
+
+	var d time.Duration
+	switch {
+	case now.Before(b.blocked):
+		d = b.blocked.Sub(now)
+	case b.tokens >= 1:
+		b.tokens--
+		return 0, nil
+	case b.rate > 0:
+		d = time.Duration(math.Ceil((1 - b.tokens) / b.rate * float64(time.Second)))
+	default:
+		d = time.Hour
+	}
+
+	if b.reject {
+		return 0, ErrRateLimited
+	}
+	return d, nil
+}
+
+func (b *TokenBucket) refill(now time.Time) {
+	if !b.last.IsZero() && now.After(b.last) {
+		b.tokens = math.Min(b.capacity, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	}
+	b.last = now
+}
+
+// Update resets the bucket to the allowance reported by the API.
+func (b *TokenBucket) Update(limit, remaining int, reset time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	b.refill(now)
+
+	b.capacity = float64(limit)
+	b.rate = float64(limit) / time.Hour.Seconds()
+	b.tokens = float64(remaining)
+	b.blocked = time.Time{}
+	if remaining <= 0 && reset.After(now) {
+		b.blocked = reset
+	}
+}
+
+type rateLimitUpdater interface {
+	Update(limit, remaining int, reset time.Time)
+}
+
+func updateRateLimit(u rateLimitUpdater, h http.Header) {
+	limit, err := strconv.Atoi(h.Get("X-FeatureRateLimit-Limit"))
+	if err != nil {
+		return
+	}
+	remaining, err := strconv.Atoi(h.Get("X-FeatureRateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+	reset, _ := http.ParseTime(h.Get("X-FeatureRateLimit-Reset"))
+	u.Update(limit, remaining, reset)
+}
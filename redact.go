@@ -0,0 +1,174 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+)
+
+// redacted replaces the secrets in the printed configurations and errors
+const redacted = "REDACTED"
+
+// Config is a view of the configuration of a Service safe for logging,
+// the secrets are REDACTED when set.
+type Config struct {
+	CloudName          string
+	APIKey             string
+	APISecret          string
+	SecondaryAPIKey    string `json:",omitempty"`
+	SecondaryAPISecret string `json:",omitempty"`
+	OAuth              bool   // Authenticated by a TokenSource
+	APIURL             string
+	DeliveryURL        string
+	FolderPrefix       string `json:",omitempty"`
+	DryRun             bool
+}
+
+// Redacted returns the configuration of the service, without its secrets
+func (s *Service) Redacted() Config {
+	return Config{
+		CloudName:          s.cloudName,
+		APIKey:             s.apiKey,
+		APISecret:          redact(s.apiSecret),
+		SecondaryAPIKey:    s.secondaryAPIKey,
+		SecondaryAPISecret: redact(s.secondaryAPISecret),
+		OAuth:              s.tokenSource != nil,
+		APIURL:             s.apiURL,
+		DeliveryURL:        s.deliveryURL,
+		FolderPrefix:       s.folderPrefix,
+		DryRun:             s.dryRun != nil,
+	}
+}
+
+// String returns the cloudinary:// URI of the configuration
+func (c Config) String() string {
+	u := url.URL{Scheme: "cloudinary", Host: c.CloudName}
+	if c.APISecret != "" {
+		u.User = url.UserPassword(c.APIKey, c.APISecret)
+	} else {
+		u.User = url.User(c.APIKey)
+	}
+	return u.String()
+}
+
+// String returns the cloudinary:// URI of the service, with its secret
+// REDACTED
+func (s *Service) String() string {
+	return s.Redacted().String()
+}
+
+// GoString keeps the secrets out of the %#v verb
+func (s *Service) GoString() string {
+	return "cloudinary.Service(" + s.String() + ")"
+}
+
+// Format prints String, or GoString for %#v, whatever the verb and
+// whether the service is printed by value or by pointer
+func (s Service) Format(f fmt.State, verb rune) {
+	formatRedacted(f, verb, s.String(), s.GoString())
+}
+
+// String returns the account:// URI of the account, with its secret REDACTED
+func (a *Account) String() string {
+	u := url.URL{Scheme: "account", User: url.UserPassword(a.apiKey, redact(a.apiSecret)), Host: a.accountID}
+	return u.String()
+}
+
+// GoString keeps the secret out of the %#v verb
+func (a *Account) GoString() string {
+	return "cloudinary.Account(" + a.String() + ")"
+}
+
+// Format prints String, or GoString for %#v, as Service.Format does
+func (a Account) Format(f fmt.State, verb rune) {
+	formatRedacted(f, verb, a.String(), a.GoString())
+}
+
+// String prints the fields of the access key, with its secret REDACTED
+func (k AccessKey) String() string {
+	type accessKey AccessKey
+	k.Secret = redact(k.Secret)
+	return fmt.Sprintf("%+v", accessKey(k))
+}
+
+// GoString keeps the secret out of the %#v verb
+func (k AccessKey) GoString() string {
+	type accessKey AccessKey
+	k.Secret = redact(k.Secret)
+	return "cloudinary.AccessKey" + strings.TrimPrefix(fmt.Sprintf("%#v", accessKey(k)), "cloudinary.accessKey")
+}
+
+// Format prints String, or GoString for %#v, as Service.Format does,
+// e.g. within a SubAccount
+func (k AccessKey) Format(f fmt.State, verb rune) {
+	formatRedacted(f, verb, k.String(), k.GoString())
+}
+
+// String returns the token REDACTED
+func (t StaticToken) String() string {
+	return redact(string(t))
+}
+
+// GoString keeps the token out of the %#v verb
+func (t StaticToken) GoString() string {
+	return "cloudinary.StaticToken(" + t.String() + ")"
+}
+
+// Format prints String, or GoString for %#v, as Service.Format does
+func (t StaticToken) Format(f fmt.State, verb rune) {
+	formatRedacted(f, verb, t.String(), t.GoString())
+}
+
+// formatRedacted writes s, or gs for %#v, the other verbs and flags
+// aside, for none of them to print the fields as is
+func formatRedacted(f fmt.State, verb rune, s, gs string) {
+	if verb == 'v' && f.Flag('#') {
+		io.WriteString(f, gs)
+		return
+	}
+	io.WriteString(f, s)
+}
+
+func redact(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	return redacted
+}
+
+// redactURI replaces the password of a URI, even one that can't be parsed
+func redactURI(uri string) string {
+	i := strings.Index(uri, "://")
+	if i < 0 {
+		return uri
+	}
+	rest := uri[i+3:]
+	at := strings.LastIndex(rest, "@")
+	if at < 0 {
+		return uri
+	}
+	colon := strings.Index(rest[:at], ":")
+	if colon < 0 {
+		return uri
+	}
+	return uri[:i+3] + rest[:colon+1] + redacted + rest[at:]
+}
+
+// redactParseError keeps the secrets of a URI out of its parse error
+func redactParseError(err error) error {
+	if e, ok := err.(*url.Error); ok {
+		e.URL = redactURI(e.URL)
+		if _, ok := e.Err.(url.EscapeError); ok {
+			// Quotes a piece of the URI, maybe of the secret
+			e.Err = url.EscapeError(redacted)
+		}
+	}
+	return err
+}
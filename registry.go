@@ -0,0 +1,173 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// AssetRecord is the local copy of an uploaded asset kept by a Registry.
+type AssetRecord struct {
+	PublicID     string
+	ResourceType string
+	Type         string
+	Format       string
+	Version      uint
+	Size         int // In bytes
+	ETag         string
+	SecureURL    string
+	Tags         []string
+	UpdatedAt    time.Time
+}
+
+// Registry mirrors the assets, e.g. to a local database.
+// Implementations must be safe for concurrent use.
+type Registry interface {
+	// Upsert stores the record or replaces the one with the same resource
+	// type, type and public ID
+	Upsert(ctx context.Context, r *AssetRecord) error
+	// Delete removes the record, if any
+	Delete(ctx context.Context, resourceType, typ, publicID string) error
+}
+
+// WithRegistry keeps r up to date after the successful uploads and destroys.
+// Registry failures don't fail the operations, they are given to onError,
+// nil ignores them.
+func WithRegistry(r Registry, onError func(error)) Option {
+	if onError == nil {
+		onError = func(error) {}
+	}
+	return func(s *Service) error {
+		return WithHooks(Hooks{
+			OnUploadSuccess: func(e UploadEvent, resp *Response) {
+				if resp == nil || resp.PublicID == "" {
+					return
+				}
+				rec := &AssetRecord{
+					PublicID:     resp.PublicID,
+					ResourceType: resp.ResourceType,
+					Type:         e.Options.Type,
+					Format:       resp.Format,
+					Version:      resp.Version,
+					Size:         resp.Size,
+					ETag:         resp.ETag,
+					SecureURL:    resp.SecureURL,
+					Tags:         e.Options.Tags,
+					UpdatedAt:    s.now(),
+				}
+				if rec.ResourceType == "" {
+					rec.ResourceType = e.Options.resourceType()
+				}
+				if rec.Type == "" {
+					rec.Type = "upload"
+				}
+				if err := r.Upsert(e.Context, rec); err != nil {
+					onError(fmt.Errorf("registry upsert %s: %w", rec.PublicID, err))
+				}
+			},
+			OnDestroy: func(e DestroyEvent) {
+				if e.Err != nil || e.DryRun {
+					return
+				}
+				rt, typ := (&UploadOptions{ResourceType: e.ResourceType}).resourceType(), e.Type
+				if typ == "" {
+					typ = "upload"
+				}
+				if err := r.Delete(e.Context, rt, typ, e.PublicID); err != nil {
+					onError(fmt.Errorf("registry delete %s: %w", e.PublicID, err))
+				}
+			},
+		})(s)
+	}
+}
+
+// SQLRegistry is a Registry storing the records in a SQL table,
+// see CreateTable for its schema. It only uses portable SQL.
+type SQLRegistry struct {
+	// Placeholder returns the bind parameter n (from 1), nil uses ? as
+	// MySQL and SQLite do, e.g. use "$" + n for PostgreSQL
+	Placeholder func(n int) string
+
+	db    *sql.DB
+	table string
+}
+
+// NewSQLRegistry returns a registry stored in table of db, the table name
+// is used as is in the queries
+func NewSQLRegistry(db *sql.DB, table string) *SQLRegistry {
+	return &SQLRegistry{db: db, table: table}
+}
+
+// CreateTable creates the table of the registry
+func (r *SQLRegistry) CreateTable(ctx context.Context) error {
+	_, err := r.db.ExecContext(ctx, `CREATE TABLE `+r.table+` (
+	resource_type VARCHAR(16) NOT NULL,
+	type VARCHAR(16) NOT NULL,
+	public_id VARCHAR(255) NOT NULL,
+	format VARCHAR(16) NOT NULL,
+	version BIGINT NOT NULL,
+	bytes BIGINT NOT NULL,
+	etag VARCHAR(32) NOT NULL,
+	secure_url VARCHAR(1024) NOT NULL,
+	tags VARCHAR(1024) NOT NULL,
+	updated_at TIMESTAMP NOT NULL,
+	PRIMARY KEY (resource_type, type, public_id)
+)`)
+	return err
+}
+
+// Upsert replaces the record within a transaction
+func (r *SQLRegistry) Upsert(ctx context.Context, rec *AssetRecord) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, r.deleteQuery(), rec.ResourceType, rec.Type, rec.PublicID); err != nil {
+		tx.Rollback()
+		return err
+	}
+	_, err = tx.ExecContext(ctx,
+		fmt.Sprintf(`INSERT INTO %s (resource_type, type, public_id, format, version, bytes, etag, secure_url, tags, updated_at) VALUES (%s)`,
+			r.table, r.placeholders(10)),
+		rec.ResourceType, rec.Type, rec.PublicID, rec.Format, int64(rec.Version), rec.Size,
+		rec.ETag, rec.SecureURL, strings.Join(rec.Tags, ","), rec.UpdatedAt,
+	)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Delete removes the record
+func (r *SQLRegistry) Delete(ctx context.Context, resourceType, typ, publicID string) error {
+	_, err := r.db.ExecContext(ctx, r.deleteQuery(), resourceType, typ, publicID)
+	return err
+}
+
+func (r *SQLRegistry) deleteQuery() string {
+	p := strings.Split(r.placeholders(3), ", ")
+	return fmt.Sprintf(`DELETE FROM %s WHERE resource_type = %s AND type = %s AND public_id = %s`, r.table, p[0], p[1], p[2])
+}
+
+func (r *SQLRegistry) placeholders(n int) string {
+	p := make([]string, n)
+	for i := range p {
+		if r.Placeholder == nil {
+			p[i] = "?"
+		} else {
+			p[i] = r.Placeholder(i + 1)
+		}
+	}
+	return strings.Join(p, ", ")
+}
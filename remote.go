@@ -0,0 +1,54 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// UploadByRemoteFetch downloads the file at addr and uploads it, for the
+// sources Cloudinary can't reach by itself, e.g. those requiring header
+// credentials or on a private network. The download is streamed into the
+// upload and made again on retries, through the HTTP client of the service.
+// opts may be nil.
+func (s *Service) UploadByRemoteFetch(ctx context.Context, addr string, header http.Header, opts *UploadOptions) (*Response, error) {
+	u, err := url.Parse(addr)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("unsupported remote file scheme %q", u.Scheme)
+	}
+	return s.Upload(ctx, s.remoteBody(ctx, addr, header), opts)
+}
+
+// remoteBody opens the response body of a GET of addr
+func (s *Service) remoteBody(ctx context.Context, addr string, header http.Header) BodyFunc {
+	return func() (io.ReadCloser, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, addr, nil)
+		if err != nil {
+			return nil, err
+		}
+		for key, values := range header {
+			req.Header[key] = values
+		}
+
+		resp, err := s.client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, fmt.Errorf("fetching the remote file from %s: %s", req.URL.Host, resp.Status)
+		}
+		return resp.Body, nil
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// UploadState is the progress of a chunked upload.
+type UploadState struct {
+	ID        string    `json:"id"`   // Unique upload ID of the chunks
+	Sent      int64     `json:"sent"` // Bytes acknowledged from the start
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// UploadStateStore persists the progress of the chunked uploads, so they
+// can resume after a restart. Implementations must be safe for concurrent use.
+type UploadStateStore interface {
+	// Load returns the state saved under key, nil if none
+	Load(key string) (*UploadState, error)
+	Save(key string, state *UploadState) error
+	// Delete removes the state once the upload is done
+	Delete(key string) error
+}
+
+// WithUploadStateStore saves the progress of the chunked uploads given an
+// UploadOptions.IdempotencyKey, an upload with the same key resuming from
+// the last acknowledged chunk. The file must then be the same.
+func WithUploadStateStore(store UploadStateStore) Option {
+	return func(s *Service) error {
+		if store == nil {
+			return errors.New("nil upload state store")
+		}
+		s.uploadStates = store
+		return nil
+	}
+}
+
+// FileUploadStateStore is an UploadStateStore keeping each state as a JSON
+// file in a directory.
+type FileUploadStateStore struct {
+	mu  sync.Mutex
+	dir string
+}
+
+// NewFileUploadStateStore returns a store in dir, creating it if needed
+func NewFileUploadStateStore(dir string) (*FileUploadStateStore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	return &FileUploadStateStore{dir: dir}, nil
+}
+
+// Load reads the state file, if any
+func (f *FileUploadStateStore) Load(key string) (*UploadState, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	b, err := ioutil.ReadFile(f.path(key))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	state := &UploadState{}
+	if err := json.Unmarshal(b, state); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// Save writes the state file atomically
+func (f *FileUploadStateStore) Save(key string, state *UploadState) error {
+	b, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	tmp, err := ioutil.TempFile(f.dir, ".state-")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	return os.Rename(tmp.Name(), f.path(key))
+}
+
+// Delete removes the state file
+func (f *FileUploadStateStore) Delete(key string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	err := os.Remove(f.path(key))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// path hashes the key, which may hold any character
+func (f *FileUploadStateStore) path(key string) string {
+	return filepath.Join(f.dir, uploadID(key)+".json")
+}
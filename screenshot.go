@@ -0,0 +1,25 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+// URL2PNGType is the delivery type of the website screenshots taken by
+// the URL2PNG add-on, the public ID being the address of the page
+const URL2PNGType = "url2png"
+
+// ScreenshotURL returns the signed URL of a screenshot of the page at
+// addr, opts may set e.g. a transformation to make a thumbnail of it
+func (s *Service) ScreenshotURL(addr string, opts *URLOptions) string {
+	o := URLOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	o.Type = URL2PNGType
+	o.ResourceType = "image"
+	// The add-on only serves signed URLs
+	o.Signed = true
+	return s.URL(addr, &o)
+}
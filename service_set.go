@@ -0,0 +1,91 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"sync"
+)
+
+// ServiceSet holds the services of many clouds keyed by tenant,
+// all of them sharing a single HTTP transport.
+// It is safe for concurrent use.
+type ServiceSet struct {
+	// NewLimiter, when set, gives every tenant its own limiter on Add
+	NewLimiter func(tenant string) Limiter
+
+	client   http.Client
+	opts     []Option
+	mu       sync.RWMutex
+	services map[string]*Service
+}
+
+// NewServiceSet returns an empty set, opts are applied to every tenant added.
+func NewServiceSet(opts ...Option) *ServiceSet {
+	return &ServiceSet{
+		client:   http.Client{Transport: NewTransport(DefaultTransportConfig())},
+		opts:     opts,
+		services: map[string]*Service{},
+	}
+}
+
+// Add dials the cloudinary:// uri of a tenant, replacing any previous service
+// of the same tenant. The tenant opts are applied after the set ones.
+func (ss *ServiceSet) Add(tenant, uri string, opts ...Option) (*Service, error) {
+	if tenant == "" {
+		return nil, errors.New("empty tenant")
+	}
+
+	all := []Option{WithHTTPClient(&ss.client)}
+	if ss.NewLimiter != nil {
+		all = append(all, WithLimiter(ss.NewLimiter(tenant)))
+	}
+	all = append(all, ss.opts...)
+	all = append(all, opts...)
+
+	s, err := Dial(uri, all...)
+	if err != nil {
+		return nil, err
+	}
+
+	ss.mu.Lock()
+	ss.services[tenant] = s
+	ss.mu.Unlock()
+
+	return s, nil
+}
+
+// Get returns the service of a tenant
+func (ss *ServiceSet) Get(tenant string) (*Service, bool) {
+	ss.mu.RLock()
+	defer ss.mu.RUnlock()
+
+	s, ok := ss.services[tenant]
+	return s, ok
+}
+
+// Remove drops the service of a tenant
+func (ss *ServiceSet) Remove(tenant string) {
+	ss.mu.Lock()
+	delete(ss.services, tenant)
+	ss.mu.Unlock()
+}
+
+// Tenants returns the sorted tenants of the set
+func (ss *ServiceSet) Tenants() []string {
+	ss.mu.RLock()
+	defer ss.mu.RUnlock()
+
+	tenants := make([]string, 0, len(ss.services))
+	for tenant := range ss.services {
+		tenants = append(tenants, tenant)
+	}
+	sort.Strings(tenants)
+	return tenants
+}
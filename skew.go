@@ -0,0 +1,69 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import (
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// maxClockSkew is the difference with the server Date past which the
+// signing time is corrected. The server only rejects signatures as stale
+// past an hour of skew, a minute leaves plenty of margin.
+const maxClockSkew = time.Minute
+
+// WithClockOffset shifts the signing timestamps by d,
+// for hosts whose clock is known to drift.
+func WithClockOffset(d time.Duration) Option {
+	return func(s *Service) error {
+		s.ownState()
+		atomic.StoreInt64(&s.state.clockOffset, int64(d))
+		return nil
+	}
+}
+
+// WithServerTime keeps the signing timestamps in line with the Date header
+// of the API responses, so uploads keep working on skewed hosts. A request
+// rejected because of a skewed timestamp is signed and sent once again.
+func WithServerTime() Option {
+	return func(s *Service) error {
+		s.serverTime = true
+		return nil
+	}
+}
+
+func (s *Service) signingTime() time.Time {
+	return s.now().Add(time.Duration(atomic.LoadInt64(&s.state.clockOffset)))
+}
+
+// observeDate derives the clock offset from the server Date
+func (s *Service) observeDate(h http.Header) {
+	date, err := http.ParseTime(h.Get("Date"))
+	if err != nil {
+		return
+	}
+
+	offset := date.Sub(s.now())
+	if abs(offset-time.Duration(atomic.LoadInt64(&s.state.clockOffset))) < maxClockSkew {
+		return
+	}
+	atomic.StoreInt64(&s.state.clockOffset, int64(offset))
+}
+
+// clockCorrected tells whether the clock offset changed significantly
+// since a request was signed with the previous offset
+func (s *Service) clockCorrected(previous int64) bool {
+	return s.serverTime && abs(time.Duration(atomic.LoadInt64(&s.state.clockOffset)-previous)) >= maxClockSkew
+}
+
+func abs(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
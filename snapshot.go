@@ -0,0 +1,118 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// MetadataRecord is a line of a metadata snapshot, see ExportMetadata.
+type MetadataRecord struct {
+	PublicID     string                 `json:"public_id"`
+	ResourceType string                 `json:"resource_type"`
+	Type         string                 `json:"type"`
+	Tags         []string               `json:"tags,omitempty"`
+	Context      Context                `json:"context,omitempty"`
+	Metadata     map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// ExportMetadata writes the public ID, tags, context and structured
+// metadata of every image, video and raw asset of the upload type to w as
+// JSON lines, those under the folder prefix only if any. The assets are
+// streamed, the snapshot is never held in memory.
+func (s *Service) ExportMetadata(ctx context.Context, w io.Writer) error {
+	enc := json.NewEncoder(w)
+	for _, rt := range []string{"image", "video", "raw"} {
+		it := s.IterateResources(ctx, rt, &ResourcesQuery{
+			Type:       "upload",
+			Prefix:     s.folderPrefix,
+			MaxResults: 500,
+			Tags:       true,
+			Context:    true,
+			Metadata:   true,
+		})
+		for it.Next() {
+			r := it.Resource()
+			if r.ResourceType == "" {
+				r.ResourceType = rt
+			}
+			err := enc.Encode(MetadataRecord{
+				PublicID:     r.PublicID,
+				ResourceType: r.ResourceType,
+				Type:         r.Type,
+				Tags:         r.Tags,
+				Context:      r.Context,
+				Metadata:     r.Metadata,
+			})
+			if err != nil {
+				it.Close()
+				return err
+			}
+		}
+		if err := it.Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ImportMetadata applies the records of a snapshot written by ExportMetadata
+// to the assets of the same public IDs, e.g. to restore a backup or migrate
+// to another cloud. Tags, context and metadata missing from a record are
+// left unchanged. It stops at the first error, the records applied so far
+// are kept.
+func (s *Service) ImportMetadata(ctx context.Context, r io.Reader) error {
+	dec := json.NewDecoder(r)
+	for n := 1; ; n++ {
+		var rec MetadataRecord
+		if err := dec.Decode(&rec); errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("metadata record %d: %w", n, err)
+		}
+		if len(rec.Tags) == 0 && len(rec.Context) == 0 && len(rec.Metadata) == 0 {
+			continue
+		}
+
+		_, err := s.UpdateResource(ctx, rec.PublicID, &UpdateOptions{
+			ResourceType: rec.ResourceType,
+			Type:         rec.Type,
+			Tags:         rec.Tags,
+			Context:      rec.Context,
+			Metadata:     rec.Metadata,
+		})
+		if err != nil {
+			return fmt.Errorf("metadata of %s: %w", rec.PublicID, err)
+		}
+	}
+}
+
+// encodeMetadata encodes structured metadata values, the strings as is and
+// the others, e.g. the lists of set fields, as JSON
+func encodeMetadata(m map[string]interface{}) (string, error) {
+	escape := strings.NewReplacer(`=`, `\=`, `|`, `\|`)
+	pairs := make([]string, 0, len(m))
+	for key, value := range m {
+		v, ok := value.(string)
+		if !ok {
+			b, err := json.Marshal(value)
+			if err != nil {
+				return "", fmt.Errorf("metadata %s: %w", key, err)
+			}
+			v = string(b)
+		}
+		pairs = append(pairs, escape.Replace(key)+"="+escape.Replace(v))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, "|"), nil
+}
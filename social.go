@@ -0,0 +1,56 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"strings"
+)
+
+// Delivery types of the images Cloudinary fetches from social networks,
+// the public ID being the identifier on the network
+const (
+	FacebookType    = "facebook"     // Facebook user ID or name
+	TwitterType     = "twitter"      // Twitter user ID
+	TwitterNameType = "twitter_name" // Twitter screen name
+	GravatarType    = "gravatar"     // MD5 of the email, see GravatarURL
+	YouTubeType     = "youtube"      // YouTube video ID, for its thumbnail
+)
+
+// remoteTypes are the delivery types whose public IDs are not stored
+// in the cloud, hence never prefixed
+var remoteTypes = map[string]bool{
+	FacebookType:    true,
+	TwitterType:     true,
+	TwitterNameType: true,
+	GravatarType:    true,
+	YouTubeType:     true,
+	URL2PNGType:     true,
+}
+
+// SocialURL returns the URL of a profile picture or a video thumbnail
+// fetched from a social network, e.g. SocialURL(FacebookType, "zuck", nil).
+// The format is jpg unless set by opts.
+func (s *Service) SocialURL(typ, id string, opts *URLOptions) string {
+	o := URLOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	o.Type = typ
+	o.ResourceType = "image"
+	if o.Format == "" {
+		o.Format = "jpg"
+	}
+	return s.URL(id, &o)
+}
+
+// GravatarURL returns the URL of the Gravatar picture of an email
+func (s *Service) GravatarURL(email string, opts *URLOptions) string {
+	h := md5.Sum([]byte(strings.ToLower(strings.TrimSpace(email))))
+	return s.SocialURL(GravatarType, hex.EncodeToString(h[:]), opts)
+}
@@ -0,0 +1,143 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// SpriteOptions are the optional parameters of GenerateSprite.
+type SpriteOptions struct {
+	// Transformation is applied to each image, e.g. c_fit,w_64,h_64
+	Transformation  string
+	Format          string // Of the sprite image, png by default
+	NotificationURL string
+}
+
+// Sprite is a single image merging the images of a tag, along with the
+// CSS positioning each of them.
+type Sprite struct {
+	PublicID       string `json:"public_id"`
+	Version        uint   `json:"version"`
+	CSSURL         string `json:"css_url"`
+	SecureCSSURL   string `json:"secure_css_url"`
+	ImageURL       string `json:"image_url"`
+	SecureImageURL string `json:"secure_image_url"`
+	JSONURL        string `json:"json_url"`
+	SecureJSONURL  string `json:"secure_json_url"`
+	// ImageInfos are the positions in the sprite, by public ID
+	ImageInfos map[string]SpriteImage `json:"image_infos"`
+}
+
+// SpriteImage is the position of an image in its sprite.
+type SpriteImage struct {
+	X      int `json:"x"`
+	Y      int `json:"y"`
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+// GenerateSprite merges the images having tag into a sprite, opts may be nil
+func (s *Service) GenerateSprite(ctx context.Context, tag string, opts *SpriteOptions) (*Sprite, error) {
+	if tag == "" {
+		return nil, errors.New("empty sprite tag")
+	}
+	if opts == nil {
+		opts = &SpriteOptions{}
+	}
+
+	params := map[string]string{"tag": tag}
+	if opts.Transformation != "" {
+		params["transformation"] = opts.Transformation
+	}
+	if opts.Format != "" {
+		params["format"] = opts.Format
+	}
+	if opts.NotificationURL != "" {
+		params["notification_url"] = opts.NotificationURL
+	}
+
+	r, err := s.newRequest(fmt.Sprintf(uploadAPIFmt, s.apiURL, s.cloudName, "image", "sprite"), http.MethodPost, params)
+	if err != nil {
+		return nil, err
+	}
+	r.ctx = ctx
+	r.mutates = true
+
+	sprite := &Sprite{}
+	if err := s.call(r, sprite); err != nil {
+		return nil, err
+	}
+	return sprite, nil
+}
+
+// SpriteCSSOptions rewrite the CSS of a sprite, zero values are left out.
+type SpriteCSSOptions struct {
+	// ClassPrefix is prepended to the class names, e.g. icon- turns
+	// .logo into .icon-logo
+	ClassPrefix string
+	// Host replaces the one of the sprite image URL, e.g. a CDN CNAME
+	Host string
+}
+
+var (
+	spriteClass = regexp.MustCompile(`(^|[\s,}])\.([A-Za-z_][\w-]*)`)
+	spriteHost  = regexp.MustCompile(`url\((['"]?)((?:https?:)?//)[^/'")]+`)
+)
+
+// SpriteCSS downloads the CSS of a sprite over https, rewritten as set
+// by opts, which may be nil
+func (s *Service) SpriteCSS(ctx context.Context, sprite *Sprite, opts *SpriteCSSOptions) ([]byte, error) {
+	addr := sprite.SecureCSSURL
+	if addr == "" {
+		addr = sprite.CSSURL
+	}
+	if addr == "" {
+		return nil, errors.New("sprite without CSS URL")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, addr, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching the sprite CSS: %s", resp.Status)
+	}
+	css, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts != nil && opts.ClassPrefix != "" {
+		css = spriteClass.ReplaceAll(css, []byte("$1."+strings.ReplaceAll(opts.ClassPrefix, "$", "$$")+"$2"))
+	}
+	if opts != nil && opts.Host != "" {
+		css = spriteHost.ReplaceAll(css, []byte("url(${1}${2}"+strings.ReplaceAll(opts.Host, "$", "$$")))
+	}
+	return css, nil
+}
+
+// SaveSpriteCSS writes the CSS of a sprite to the file at path,
+// see SpriteCSS
+func (s *Service) SaveSpriteCSS(ctx context.Context, sprite *Sprite, path string, opts *SpriteCSSOptions) error {
+	css, err := s.SpriteCSS(ctx, sprite, opts)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, css, 0644)
+}
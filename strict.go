@@ -0,0 +1,137 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Transformation is a transformation known by the account.
+type Transformation struct {
+	Name             string `json:"name"` // e.g. c_fill,w_100 or the name of a named one
+	AllowedForStrict bool   `json:"allowed_for_strict"`
+	Used             bool   `json:"used"`
+	Named            bool   `json:"named"`
+}
+
+// TransformationList is a page of transformations.
+type TransformationList struct {
+	Transformations []Transformation `json:"transformations"`
+	NextCursor      string           `json:"next_cursor,omitempty"`
+}
+
+// Transformations lists a page of transformations, cursor is empty for the first one
+func (s *Service) Transformations(ctx context.Context, cursor string) (*TransformationList, error) {
+	query := url.Values{"max_results": {"500"}}
+	if cursor != "" {
+		query.Set("next_cursor", cursor)
+	}
+
+	r, err := s.newAdminRequest(http.MethodGet, "transformations", query, nil)
+	if err != nil {
+		return nil, err
+	}
+	r.ctx = ctx
+
+	res := &TransformationList{}
+	if err := s.call(r, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// AllowForStrict toggles allowed_for_strict on each transformation,
+// it stops at the first error
+func (s *Service) AllowForStrict(ctx context.Context, names []string, allowed bool) error {
+	for _, name := range names {
+		query := url.Values{
+			"transformation":     {name},
+			"allowed_for_strict": {strconv.FormatBool(allowed)},
+		}
+		r, err := s.newAdminRequest(http.MethodPut, "transformations", query, nil)
+		if err != nil {
+			return err
+		}
+		r.ctx = ctx
+		r.mutates = true
+
+		var res struct {
+			Message string `json:"message"`
+		}
+		if err := s.call(r, &res); err != nil {
+			return fmt.Errorf("transformation %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// StrictAllowlist is the set of transformations allowed for strict
+// transformations, by name.
+type StrictAllowlist map[string]bool
+
+// StrictAllowlist fetches the transformations allowed for strict transformations
+func (s *Service) StrictAllowlist(ctx context.Context) (StrictAllowlist, error) {
+	allowlist := StrictAllowlist{}
+	var cursor string
+	for {
+		list, err := s.Transformations(ctx, cursor)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range list.Transformations {
+			if t.AllowedForStrict {
+				allowlist[t.Name] = true
+			}
+		}
+		if cursor = list.NextCursor; cursor == "" {
+			return allowlist, nil
+		}
+	}
+}
+
+// A transformation component of a delivery URL, e.g. c_fill,w_100
+var transformationComponent = regexp.MustCompile(`^[a-z]{1,3}_[^,/]+(,[a-z]{1,3}_[^,/]+)*$`)
+
+// Check returns an error when the delivery URL uses a transformation
+// that a strict transformations account would refuse to generate
+func (a StrictAllowlist) Check(deliveryURL string) error {
+	t := urlTransformation(deliveryURL)
+	if t == "" || a[t] || a[strings.TrimPrefix(t, "t_")] {
+		return nil
+	}
+	return fmt.Errorf("transformation %q is not allowed for strict transformations", t)
+}
+
+// urlTransformation returns the transformation chain of a delivery URL,
+// i.e. the components between the delivery type and the version or public ID
+func urlTransformation(deliveryURL string) string {
+	u, err := url.Parse(deliveryURL)
+	if err != nil {
+		return ""
+	}
+
+	// /:cloud_name/:resource_type/:type/...
+	parts := strings.Split(strings.TrimPrefix(u.Path, "/"), "/")
+	if len(parts) < 4 {
+		return ""
+	}
+
+	var chain []string
+	for _, part := range parts[3 : len(parts)-1] {
+		if !transformationComponent.MatchString(part) {
+			break
+		}
+		chain = append(chain, part)
+	}
+	return strings.Join(chain, "/")
+}
@@ -0,0 +1,148 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// Subtitles are raw vtt or srt files named after their video, e.g.
+// intro.en.vtt for the English subtitles of the intro video, see
+// SubtitlesPublicID. They are delivered burnt into the video with
+// SubtitlesOverlay.
+
+// SubtitlesPublicID returns the public ID of the subtitles of a video in
+// language, if any, and format, vtt or srt
+func SubtitlesPublicID(videoPublicID, language, format string) string {
+	if language == "" {
+		return TranscriptPublicID(videoPublicID, format)
+	}
+	return videoPublicID + "." + language + "." + format
+}
+
+// SubtitlesOptions are the optional parameters of UploadSubtitles.
+type SubtitlesOptions struct {
+	Language string // e.g. en
+	// Format is vtt or srt, the extension of a file path by default
+	Format string
+}
+
+// SubtitleTrack is a subtitles file of a video.
+type SubtitleTrack struct {
+	PublicID string
+	Language string // Empty for the transcripts
+	Format   string // vtt or srt
+}
+
+// UploadSubtitles uploads a vtt or srt file, given as to Upload, as the
+// subtitles of a video, replacing those of the same language if any
+func (s *Service) UploadSubtitles(ctx context.Context, videoPublicID string, file interface{}, opts *SubtitlesOptions) (*Response, error) {
+	if err := ValidatePublicID(videoPublicID); err != nil {
+		return nil, err
+	}
+	if opts == nil {
+		opts = &SubtitlesOptions{}
+	}
+	format := opts.Format
+	if p, ok := file.(string); ok && format == "" && !remoteFile.MatchString(p) {
+		format = strings.TrimPrefix(path.Ext(p), ".")
+	}
+	format = strings.ToLower(format)
+	if format != "vtt" && format != "srt" {
+		return nil, fmt.Errorf("unsupported subtitles format %q", format)
+	}
+
+	overwrite := true
+	up := &UploadOptions{
+		ResourceType: "raw",
+		PublicID:     SubtitlesPublicID(videoPublicID, opts.Language, format),
+		Overwrite:    &overwrite,
+		Invalidate:   true,
+	}
+	if opts.Language != "" {
+		up.Context = map[string]string{"language": opts.Language}
+	}
+	return s.Upload(ctx, file, up)
+}
+
+// SubtitleTracks lists the subtitles of a video, transcripts included, the
+// raw files of the upload type uploaded by UploadSubtitles
+func (s *Service) SubtitleTracks(ctx context.Context, videoPublicID string) ([]SubtitleTrack, error) {
+	prefix := s.prefixed(videoPublicID) + "."
+
+	var tracks []SubtitleTrack
+	it := s.IterateResources(ctx, "raw", &ResourcesQuery{Type: "upload", Prefix: prefix, MaxResults: 500})
+	for it.Next() {
+		id := it.Resource().PublicID
+		if !strings.HasPrefix(id, prefix) {
+			continue
+		}
+		parts := strings.Split(strings.TrimPrefix(id, prefix), ".")
+		format := parts[len(parts)-1]
+		if len(parts) > 2 || (format != "vtt" && format != "srt") {
+			// Another file or the subtitles of another video, e.g. intro.long.en.vtt
+			continue
+		}
+
+		t := SubtitleTrack{PublicID: id, Format: format}
+		if len(parts) == 2 {
+			t.Language = parts[0]
+		}
+		tracks = append(tracks, t)
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return tracks, nil
+}
+
+// SubtitlesStyle is the font of the subtitles burnt into a video,
+// zero values are left to the defaults.
+type SubtitlesStyle struct {
+	Font       string // e.g. arial
+	Size       int
+	Color      string // e.g. yellow or rgb:ffff00
+	Background string // Of the text
+}
+
+// StyledSubtitlesOverlay is SubtitlesOverlay with the subtitles drawn in
+// style, the font and size being set together, arial 20 by default
+func StyledSubtitlesOverlay(subtitlesPublicID string, style SubtitlesStyle) string {
+	var params []string
+	if style.Color != "" {
+		params = append(params, "co_"+style.Color)
+	}
+	if style.Background != "" {
+		params = append(params, "b_"+style.Background)
+	}
+
+	layer := "l_subtitles:"
+	if style.Font != "" || style.Size > 0 {
+		font, size := style.Font, style.Size
+		if font == "" {
+			font = "arial"
+		}
+		if size <= 0 {
+			size = 20
+		}
+		layer += font + "_" + strconv.Itoa(size) + ":"
+	}
+	params = append(params, layer+layerID(subtitlesPublicID))
+
+	return strings.Join(params, ",") + "/fl_layer_apply"
+}
+
+// AudioOverlay returns the transformation mixing an audio file, uploaded
+// as a video, into the sound of a video, e.g. a dubbed track after an
+// ac_none stripping the original one
+func AudioOverlay(audioPublicID string) string {
+	return "l_audio:" + layerID(audioPublicID) + "/fl_layer_apply"
+}
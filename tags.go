@@ -0,0 +1,65 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// TagOptions are the optional parameters of AddTags.
+type TagOptions struct {
+	ResourceType string // image (default), video or raw
+	Type         string // Delivery type, upload by default
+}
+
+// AddTags adds the tags to a resource, keeping its other tags, opts may be nil
+func (s *Service) AddTags(ctx context.Context, publicID string, opts *TagOptions, tags ...string) error {
+	if err := ValidatePublicID(publicID); err != nil {
+		return err
+	}
+	if len(tags) == 0 {
+		return errors.New("no tags to add")
+	}
+	if opts == nil {
+		opts = &TagOptions{}
+	}
+	typ := opts.Type
+	if typ == "" {
+		typ = "upload"
+	}
+
+	r, err := s.newRequest(
+		fmt.Sprintf(uploadAPIFmt, s.apiURL, s.cloudName, (&UploadOptions{ResourceType: opts.ResourceType}).resourceType(), "tags"),
+		http.MethodPost,
+		map[string]string{
+			"command":      "add",
+			"tag":          strings.Join(tags, ","),
+			"public_ids[]": s.prefixed(publicID),
+			"type":         typ,
+		},
+	)
+	if err != nil {
+		return err
+	}
+	r.ctx = ctx
+	r.mutates = true
+
+	var res struct {
+		PublicIDs []string `json:"public_ids"`
+	}
+	if err := s.call(r, &res); err != nil {
+		return err
+	}
+	if len(res.PublicIDs) == 0 {
+		return fmt.Errorf("resource %s not found", publicID)
+	}
+	return nil
+}
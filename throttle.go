@@ -0,0 +1,60 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// throttledReader reads at most rate bytes per second, with bursts of
+// a second worth of bytes
+type throttledReader struct {
+	io.ReadCloser
+	ctx    context.Context
+	rate   int64
+	tokens int64
+	last   time.Time
+}
+
+func throttle(ctx context.Context, rc io.ReadCloser, rate int64) io.ReadCloser {
+	return &throttledReader{ReadCloser: rc, ctx: ctx, rate: rate, tokens: rate, last: time.Now()}
+}
+
+func (t *throttledReader) Read(p []byte) (int, error) {
+	now := time.Now()
+	// The bucket holds a second worth of bytes, more would overflow
+	elapsed := now.Sub(t.last)
+	if elapsed > time.Second {
+		elapsed = time.Second
+	}
+	t.tokens += int64(elapsed) * t.rate / int64(time.Second)
+	if t.tokens > t.rate {
+		t.tokens = t.rate
+	}
+	t.last = now
+
+	if t.tokens <= 0 {
+		wait := time.Duration((1 - t.tokens) * int64(time.Second) / t.rate)
+		timer := time.NewTimer(wait)
+		select {
+		case <-t.ctx.Done():
+			timer.Stop()
+			return 0, t.ctx.Err()
+		case <-timer.C:
+		}
+		return t.Read(p)
+	}
+
+	if int64(len(p)) > t.tokens {
+		p = p[:t.tokens]
+	}
+	n, err := t.ReadCloser.Read(p)
+	t.tokens -= int64(n)
+	return n, err
+}
@@ -0,0 +1,40 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import "strings"
+
+// TranscriptPublicID returns the public ID of the raw file generated by the
+// google_speech transcription of a video, in format transcript (default),
+// vtt or srt, the latter ones being requested with google_speech:vtt:srt
+func TranscriptPublicID(videoPublicID, format string) string {
+	if format == "" {
+		format = "transcript"
+	}
+	return videoPublicID + "." + format
+}
+
+// SubtitlesOverlay returns the transformation burning the subtitles of a
+// raw transcript, vtt or srt file into a video, e.g. as the
+// URLOptions.Transformation of the video
+func SubtitlesOverlay(subtitlesPublicID string) string {
+	return "l_subtitles:" + layerID(subtitlesPublicID) + "/fl_layer_apply"
+}
+
+// Transcribed tells whether the transcription of a video is complete,
+// as reported in the info block of its upload or explicit response
+func (i *Info) Transcribed() bool {
+	if i == nil {
+		return false
+	}
+	for name, status := range i.RawConvert {
+		if strings.HasPrefix(name, "google_speech") && status.Status == "complete" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,68 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// UnusedAsset is an asset idle for a while, see UnusedAssets.
+type UnusedAsset struct {
+	Resource Resource
+	// LastActivity is the last access of the asset when tracked,
+	// else its last update, else its creation
+	LastActivity time.Time
+	Accessed     bool // LastActivity is an access
+}
+
+// UnusedAssets reports the assets matching the search expression, all of
+// them when empty, with no activity for idle, oldest activity first. It
+// only lists them, e.g. as input of a cleanup job.
+//
+// The last access is only known on accounts tracking it, the assets are
+// otherwise judged on their last update, which does not tell whether they
+// are still delivered.
+func (s *Service) UnusedAssets(ctx context.Context, expression string, idle time.Duration) ([]UnusedAsset, error) {
+	before := s.now().Add(-idle)
+
+	var unused []UnusedAsset
+	it := s.IterateSearch(ctx, &SearchQuery{Expression: expression, MaxResults: 500})
+	for it.Next() {
+		a := UnusedAsset{Resource: *it.Resource()}
+		a.LastActivity, a.Accessed = lastActivity(&a.Resource)
+		if a.LastActivity.Before(before) {
+			unused = append(unused, a)
+		}
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(unused, func(i, j int) bool {
+		return unused[i].LastActivity.Before(unused[j].LastActivity)
+	})
+	return unused, nil
+}
+
+// lastActivity returns the last access of r if known, else its last update
+// or creation, and whether it is an access
+func lastActivity(r *Resource) (time.Time, bool) {
+	if !r.AccessedAt.IsZero() {
+		return r.AccessedAt, true
+	}
+	last := r.CreatedAt
+	if u := r.LastUpdated; u != nil {
+		for _, t := range []time.Time{u.UpdatedAt, u.TagsUpdatedAt, u.ContextUpdatedAt, u.MetadataUpdatedAt, u.PublicIDUpdatedAt} {
+			if t.After(last) {
+				last = t
+			}
+		}
+	}
+	return last, false
+}
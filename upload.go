@@ -0,0 +1,499 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// UploadOptions are the optional parameters of an upload, see
+// https://cloudinary.com/documentation/image_upload_api_reference#upload_method
+// They are all sent signed, zero values are left out.
+type UploadOptions struct {
+	// ResourceType is image (default), video, raw or auto
+	ResourceType string
+	// Type is the delivery type, e.g. upload (default), private or authenticated
+	Type     string
+	PublicID string
+	Folder   string // Fixed folder mode, part of the public ID
+	// AssetFolder and DisplayName are used by clouds in dynamic folder
+	// mode instead of Folder, they are independent of the public ID
+	AssetFolder                    string
+	DisplayName                    string
+	UseFilenameAsDisplayName       bool
+	UseAssetFolderAsPublicIDPrefix bool
+	Tags                           []string
+	Context                        map[string]string
+	Metadata                       map[string]interface{} // Structured metadata by external ID
+	Overwrite                      *bool
+	UniqueFilename                 *bool
+	UseFilename                    bool
+	Invalidate                     bool
+	// CreateIfAbsent keeps the existing asset of the PublicID, if any,
+	// which is returned along with Response.Existing instead of the upload
+	CreateIfAbsent bool
+	// QualityAnalysis returns the quality_analysis scores of the image
+	QualityAnalysis bool
+	UploadPreset    string
+	NotificationURL string
+	// Async uploads in the background, the response only holds a
+	// batch ID, see NotificationHandler
+	Async bool
+
+	// Eager transformations are generated on upload, e.g. c_fill,w_100
+	// or several chained ones as c_crop,w_400/e_sepia
+	Eager []string
+	// EagerAsync generates them in the background, see EagerNotificationURL
+	EagerAsync           bool
+	EagerNotificationURL string
+
+	// Detection runs detection add-ons, a comma separated list of models
+	// such as captioning, adv_face or coco, see Response.Info
+	Detection string
+
+	// RawConvert runs a conversion add-on, e.g. google_speech to transcribe
+	// videos or google_speech:vtt to get subtitles along
+	RawConvert string
+
+	// AutoChaptering generates the chapters of a video as a vtt file,
+	// see ChaptersPublicID
+	AutoChaptering bool
+
+	// IdempotencyKey identifies the upload across retries, the same key sends
+	// the same X-Unique-Upload-Id so a request retried after a timeout can't
+	// create a duplicate asset.
+	IdempotencyKey string
+
+	// Timeout is the deadline of the whole upload, retries included
+	Timeout time.Duration
+	// MaxBytesPerSecond throttles the sending of the file content,
+	// e.g. so bulk uploads don't saturate the egress, zero is unlimited
+	MaxBytesPerSecond int64
+	// UploadPrefix sends the upload to another API endpoint than the one of
+	// the service, e.g. https://api-eu.cloudinary.com, see WithUploadPrefix
+	UploadPrefix string
+
+	// Chunked sends a local file in chunks as UploadLarge does, resuming
+	// from the saved progress given an IdempotencyKey and an UploadStateStore
+	Chunked bool
+
+	// Dedupe skips the upload when the same content already exists,
+	// the existing asset is returned with Response.Duplicate set
+	Dedupe *Dedupe
+
+	// Preflight checks a remote http(s) file with a HEAD request before
+	// calling the API, returning a *PreflightError on failure
+	Preflight *Preflight
+
+	// VerifyChecksum compares the etag and size returned by cloudinary with
+	// the local file, returning a *ChecksumError on mismatch
+	VerifyChecksum bool
+
+	// Preprocessor transforms local files before they are uploaded,
+	// e.g. StripMetadata
+	Preprocessor Preprocessor
+
+	// Extra parameters are serialized and signed verbatim, so parameters
+	// newer than this package can be used right away.
+	// They take precedence over the fields above.
+	Extra map[string]string
+}
+
+func (o *UploadOptions) resourceType() string {
+	if o == nil || o.ResourceType == "" {
+		return "image"
+	}
+	return o.ResourceType
+}
+
+// deliveryType returns the delivery type of the upload, upload by default
+func (o *UploadOptions) deliveryType() string {
+	if o == nil || o.Type == "" {
+		return "upload"
+	}
+	return o.Type
+}
+
+func (o *UploadOptions) params() (map[string]string, error) {
+	p := map[string]string{}
+	if o == nil {
+		return p, nil
+	}
+
+	set := func(key, value string) {
+		if value != "" {
+			p[key] = value
+		}
+	}
+	set("type", o.Type)
+	set("public_id", o.PublicID)
+	set("folder", o.Folder)
+	set("asset_folder", o.AssetFolder)
+	set("display_name", o.DisplayName)
+	set("tags", strings.Join(o.Tags, ","))
+	set("context", encodeContext(o.Context))
+	m, err := encodeMetadata(o.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	set("metadata", m)
+	set("upload_preset", o.UploadPreset)
+	set("notification_url", o.NotificationURL)
+	set("eager", strings.Join(o.Eager, "|"))
+	set("eager_notification_url", o.EagerNotificationURL)
+	set("detection", o.Detection)
+	set("raw_convert", o.RawConvert)
+	if o.Overwrite != nil {
+		p["overwrite"] = strconv.FormatBool(*o.Overwrite)
+	}
+	if o.CreateIfAbsent {
+		p["overwrite"] = "false"
+	}
+	if o.UniqueFilename != nil {
+		p["unique_filename"] = strconv.FormatBool(*o.UniqueFilename)
+	}
+	if o.UseFilename {
+		p["use_filename"] = "true"
+	}
+	if o.UseFilenameAsDisplayName {
+		p["use_filename_as_display_name"] = "true"
+	}
+	if o.UseAssetFolderAsPublicIDPrefix {
+		p["use_asset_folder_as_public_id_prefix"] = "true"
+	}
+	if o.Invalidate {
+		p["invalidate"] = "true"
+	}
+	if o.QualityAnalysis {
+		p["quality_analysis"] = "true"
+	}
+	if o.Async {
+		p["async"] = "true"
+	}
+	if o.EagerAsync {
+		p["eager_async"] = "true"
+	}
+	if o.AutoChaptering {
+		p["auto_chaptering"] = "true"
+	}
+
+	for key, value := range o.Extra {
+		p[key] = value
+	}
+	return p, nil
+}
+
+// encodeContext serializes key-value pairs as key=value|key2=value2,
+// escaping the separators found in keys and values
+func encodeContext(c map[string]string) string {
+	if len(c) == 0 {
+		return ""
+	}
+
+	escape := strings.NewReplacer(`=`, `\=`, `|`, `\|`)
+	pairs := make([]string, 0, len(c))
+	for key, value := range c {
+		pairs = append(pairs, escape.Replace(key)+"="+escape.Replace(value))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, "|")
+}
+
+// Schemes of the files Cloudinary fetches by itself
+var remoteFile = regexp.MustCompile(`^(https?|ftp|s3|gs)://|^data:`)
+
+// Upload uploads file to cloudinary with the given options, opts may be nil.
+// file is either:
+//   - a string holding a local path, or a remote URL fetched by cloudinary
+//   - an io.Reader, see UploadByIOReader
+//   - a BodyFunc, see UploadByBody
+//   - a []byte
+func (s *Service) Upload(ctx context.Context, file interface{}, opts *UploadOptions) (*Response, error) {
+	opts = s.uploadOptions(opts)
+	if opts != nil && opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+	return s.track(ctx, opts, func() (*Response, error) {
+		return s.uploadAny(ctx, file, opts)
+	})
+}
+
+func (s *Service) uploadAny(ctx context.Context, file interface{}, opts *UploadOptions) (*Response, error) {
+	if s.metadataTTL > 0 {
+		var values map[string]interface{}
+		if opts != nil {
+			values = opts.Metadata
+		}
+		// The mandatory fields may be set by the preset
+		if err := s.validateMetadata(ctx, values, opts == nil || opts.UploadPreset == ""); err != nil {
+			return nil, err
+		}
+	}
+	if addr, ok := file.(string); ok && opts != nil && opts.Preflight != nil {
+		if err := opts.Preflight.check(ctx, &s.client, addr); err != nil {
+			return nil, err
+		}
+	}
+	if opts != nil && opts.Preprocessor != nil {
+		rc, err := openLocal(file)
+		if err != nil {
+			return nil, err
+		}
+		if rc != nil {
+			defer rc.Close()
+			if file, err = opts.Preprocessor.Process(rc); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if opts == nil || (opts.Dedupe == nil && !opts.VerifyChecksum) {
+		return s.upload(ctx, file, opts)
+	}
+
+	if _, ok := file.(io.ReadSeeker); !ok {
+		// Keep a copy of the readers that can't be rewound to hash them
+		if r, ok := file.(io.Reader); ok {
+			b, err := ioutil.ReadAll(r)
+			if err != nil {
+				return nil, err
+			}
+			file = b
+		}
+	}
+
+	hash, size, err := contentHash(file)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Dedupe != nil && hash != "" {
+		existing, err := s.dedupe(ctx, opts.Dedupe, hash, opts)
+		if err != nil || existing != nil {
+			return existing, err
+		}
+	}
+
+	resp, err := s.upload(ctx, file, opts)
+	if err != nil || hash == "" {
+		return resp, err
+	}
+
+	if opts.VerifyChecksum {
+		if err := verifyChecksum(resp, hash, size); err != nil {
+			return resp, err
+		}
+	}
+	if opts.Dedupe != nil && opts.Dedupe.Manifest != nil {
+		err = opts.Dedupe.Manifest.Store(dedupeKey(hash, opts), resp.PublicID)
+	}
+	return resp, err
+}
+
+func (s *Service) upload(ctx context.Context, file interface{}, opts *UploadOptions) (*Response, error) {
+	if opts != nil && opts.Chunked {
+		if r, ok := file.(io.Reader); ok {
+			return s.uploadLarge(ctx, r, opts)
+		}
+		rc, err := openLocal(file)
+		if err != nil {
+			return nil, err
+		}
+		if rc != nil {
+			defer rc.Close()
+			return s.uploadLarge(ctx, rc, opts)
+		}
+	}
+
+	switch f := file.(type) {
+	case string:
+		if remoteFile.MatchString(f) {
+			return s.uploadURL(ctx, f, opts)
+		}
+		return s.uploadFile(ctx, f, opts)
+	case BodyFunc:
+		return s.uploadBody(ctx, f, opts)
+	case func() (io.ReadCloser, error):
+		return s.uploadBody(ctx, f, opts)
+	case []byte:
+		return s.uploadBody(ctx, bytesBody(f), opts)
+	case io.Reader:
+		return s.uploadReader(ctx, f, opts)
+	}
+	return nil, fmt.Errorf("unsupported file type %T", file)
+}
+
+// Explicit applies the options to an existing resource, e.g. new tags,
+// context or eager transformations, without uploading it again.
+// opts may be nil, the delivery type defaults to upload.
+func (s *Service) Explicit(ctx context.Context, publicID string, opts *UploadOptions) (*Response, error) {
+	if err := ValidatePublicID(publicID); err != nil {
+		return nil, err
+	}
+
+	params, err := opts.params()
+	if err != nil {
+		return nil, err
+	}
+	params["public_id"] = s.prefixed(publicID)
+	if params["type"] == "" {
+		params["type"] = "upload"
+	}
+
+	api, err := s.uploadAPI(opts, "explicit")
+	if err != nil {
+		return nil, err
+	}
+	r, err := s.newRequest(api, http.MethodPost, params)
+	if err != nil {
+		return nil, err
+	}
+	r.ctx = ctx
+	r.mutates = true
+
+	return s.do(r)
+}
+
+func (s *Service) newUploadRequest(ctx context.Context, opts *UploadOptions) (*request, error) {
+	opts = s.uploadOptions(opts)
+	if opts != nil {
+		if err := validatePublicIDs(opts.PublicID); err != nil {
+			return nil, err
+		}
+		if opts.CreateIfAbsent && (opts.PublicID == "" || (opts.Overwrite != nil && *opts.Overwrite)) {
+			return nil, errors.New("CreateIfAbsent needs a PublicID and no Overwrite")
+		}
+	}
+
+	params, err := opts.params()
+	if err != nil {
+		return nil, err
+	}
+	s.prefixUpload(params)
+
+	api, err := s.uploadAPI(opts, "upload")
+	if err != nil {
+		return nil, err
+	}
+	r, err := s.newRequest(api, http.MethodPost, params)
+	if err != nil {
+		return nil, err
+	}
+	r.ctx = ctx
+	r.mutates = true
+	if opts != nil {
+		r.maxRate = opts.MaxBytesPerSecond
+	}
+	if key := opts.idempotencyKey(); key != "" {
+		r.header.Set("X-Unique-Upload-Id", uploadID(key))
+	}
+	return r, nil
+}
+
+func (o *UploadOptions) idempotencyKey() string {
+	if o == nil {
+		return ""
+	}
+	return o.IdempotencyKey
+}
+
+// uploadID derives the X-Unique-Upload-Id of an idempotency key,
+// so any key fits the header format
+func uploadID(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:16])
+}
+
+func (s *Service) uploadFile(ctx context.Context, path string, opts *UploadOptions) (*Response, error) {
+	// Open file path
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return s.uploadReader(ctx, f, opts)
+}
+
+func (s *Service) uploadURL(ctx context.Context, addr string, opts *UploadOptions) (*Response, error) {
+	// Validate url
+	if _, err := url.Parse(addr); err != nil {
+		return nil, err
+	}
+
+	r, err := s.newUploadRequest(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = r.addFileURL(addr); err != nil {
+		return nil, err
+	}
+
+	return s.do(r)
+}
+
+func (s *Service) uploadReader(ctx context.Context, reader io.Reader, opts *UploadOptions) (*Response, error) {
+	r, err := s.newUploadRequest(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = r.addFile(reader); err != nil {
+		return nil, err
+	}
+	defer r.close()
+
+	return s.doUpload(r, opts)
+}
+
+func (s *Service) uploadBody(ctx context.Context, body BodyFunc, opts *UploadOptions) (*Response, error) {
+	r, err := s.newUploadRequest(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	r.open = body
+	r.replayable = true
+
+	return s.doUpload(r, opts)
+}
+
+// doUpload sends an upload request, falling back to a chunked upload
+// when the file is too large and can be read again
+func (s *Service) doUpload(r *request, opts *UploadOptions) (*Response, error) {
+	resp, err := s.do(r)
+
+	var apiErr *APIError
+	if r.replayable && errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusRequestEntityTooLarge {
+		body, err := r.open()
+		if err != nil {
+			return nil, err
+		}
+		defer body.Close()
+
+		return s.uploadLarge(r.ctx, body, opts)
+	}
+
+	return resp, err
+}
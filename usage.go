@@ -0,0 +1,146 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// UsageMetric is the consumption of a resource of the plan.
+type UsageMetric struct {
+	Usage        float64 `json:"usage"`
+	CreditsUsage float64 `json:"credits_usage,omitempty"`
+	Limit        float64 `json:"limit,omitempty"`
+	UsedPercent  float64 `json:"used_percent,omitempty"`
+}
+
+// Usage is the account usage report, see
+// https://cloudinary.com/documentation/admin_api#usage
+type Usage struct {
+	Plan             string      `json:"plan"`
+	LastUpdated      string      `json:"last_updated"`
+	Transformations  UsageMetric `json:"transformations"`
+	Objects          UsageMetric `json:"objects"`
+	Bandwidth        UsageMetric `json:"bandwidth"` // In bytes
+	Storage          UsageMetric `json:"storage"`   // In bytes
+	Credits          UsageMetric `json:"credits"`
+	Requests         int64       `json:"requests"`
+	Resources        int64       `json:"resources"`
+	DerivedResources int64       `json:"derived_resources"`
+}
+
+// Usage returns the usage report of the given day,
+// up to three months back, or the current one when date is zero
+func (s *Service) Usage(date time.Time) (*Usage, error) {
+	return s.usage(context.Background(), date)
+}
+
+func (s *Service) usage(ctx context.Context, date time.Time) (*Usage, error) {
+	query := url.Values{}
+	if !date.IsZero() {
+		query.Set("date", date.Format("02-01-2006"))
+	}
+
+	r, err := s.newAdminRequest(http.MethodGet, "usage", query, nil)
+	if err != nil {
+		return nil, err
+	}
+	r.ctx = ctx
+
+	res := &Usage{}
+	if err := s.call(r, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// UsagePoint is the usage report of a day.
+type UsagePoint struct {
+	Date time.Time `json:"date"`
+	Usage
+}
+
+// UsageSeries is a daily usage time series, oldest first.
+type UsageSeries struct {
+	Points []UsagePoint `json:"points"`
+}
+
+// UsageSeries fetches the daily usage reports from one day to another,
+// both included, one call per day
+func (s *Service) UsageSeries(ctx context.Context, from, to time.Time) (*UsageSeries, error) {
+	from = day(from)
+	to = day(to)
+	if to.Before(from) {
+		return nil, errors.New("the range ends before it starts")
+	}
+
+	series := &UsageSeries{}
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		u, err := s.usage(ctx, d)
+		if err != nil {
+			return nil, err
+		}
+		series.Points = append(series.Points, UsagePoint{Date: d, Usage: *u})
+	}
+	return series, nil
+}
+
+func day(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// usageColumns are the CSV columns written by WriteCSV
+var usageColumns = []string{
+	"date", "plan", "credits", "transformations", "objects",
+	"bandwidth_bytes", "storage_bytes", "requests", "resources", "derived_resources",
+}
+
+// WriteCSV writes the series as CSV, with a header row
+func (series *UsageSeries) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(usageColumns); err != nil {
+		return err
+	}
+
+	float := func(f float64) string {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	for _, p := range series.Points {
+		record := []string{
+			p.Date.Format("2006-01-02"),
+			p.Plan,
+			float(p.Credits.Usage),
+			float(p.Transformations.Usage),
+			float(p.Objects.Usage),
+			float(p.Bandwidth.Usage),
+			float(p.Storage.Usage),
+			strconv.FormatInt(p.Requests, 10),
+			strconv.FormatInt(p.Resources, 10),
+			strconv.FormatInt(p.DerivedResources, 10),
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteJSON writes the series as JSON
+func (series *UsageSeries) WriteJSON(w io.Writer) error {
+	return json.NewEncoder(w).Encode(series)
+}
@@ -0,0 +1,106 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import (
+	"errors"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// Version of the package, as sent in the User-Agent
+const Version = "0.1.0"
+
+// defaultUserAgent identifies the package to the API
+var defaultUserAgent = "go-cloudinary/" + Version + " (" + runtime.Version() + "; " + runtime.GOOS + "/" + runtime.GOARCH + ")"
+
+// WithUserAgent appends the identifier of the application, e.g.
+// myapp/1.2, to the User-Agent of the requests
+func WithUserAgent(app string) Option {
+	return func(s *Service) error {
+		if app = strings.TrimSpace(app); app == "" {
+			return errors.New("empty user agent")
+		}
+		s.userAgent = defaultUserAgent + " " + app
+		return nil
+	}
+}
+
+// WithAnalytics appends the _a analytics token to the unsigned delivery
+// URLs, telling Cloudinary the versions of the package and of Go. The
+// token is left out by default: Cloudinary assigns the SDK codes
+// identifying the SDKs, and none is assigned to this package yet, so the
+// code must be one assigned by Cloudinary, a single character.
+func WithAnalytics(sdkCode string) Option {
+	return func(s *Service) error {
+		if len(sdkCode) != 1 || !strings.Contains(analyticsChars, sdkCode) {
+			return errors.New("invalid analytics SDK code")
+		}
+		if s.analytics = newAnalyticsToken(sdkCode, Version, runtime.Version()); s.analytics == "" {
+			return errors.New("version can't be encoded in the analytics token")
+		}
+		return nil
+	}
+}
+
+// Parts of the analytics token
+const (
+	analyticsAlgorithm = "B"
+	analyticsFeature   = "0"
+)
+
+func newAnalyticsToken(sdkCode, version, goVersion string) string {
+	sdk, err := encodeVersion(version)
+	if err != nil {
+		return ""
+	}
+
+	// e.g. go1.27.1 is encoded as 1.27
+	tech := "0.0"
+	parts := strings.SplitN(strings.TrimPrefix(goVersion, "go"), ".", 3)
+	if len(parts) >= 2 {
+		tech = parts[0] + "." + strings.TrimRightFunc(parts[1], func(r rune) bool { return r < '0' || r > '9' })
+	}
+	encodedTech, err := encodeVersion(tech)
+	if err != nil {
+		return ""
+	}
+	return analyticsAlgorithm + sdkCode + sdk + encodedTech + analyticsFeature
+}
+
+const analyticsChars = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/"
+
+// encodeVersion encodes a version of the analytics token, its parts
+// reversed and padded to 2 digits then written 6 bits per character
+func encodeVersion(version string) (string, error) {
+	parts := strings.Split(version, ".")
+	var digits strings.Builder
+	for i := len(parts) - 1; i >= 0; i-- {
+		n, err := strconv.Atoi(parts[i])
+		if err != nil || n < 0 || n > 99 {
+			return "", errors.New("invalid version " + version)
+		}
+		if n < 10 {
+			digits.WriteByte('0')
+		}
+		digits.WriteString(strconv.Itoa(n))
+	}
+
+	num, _ := strconv.ParseUint(digits.String(), 10, 64)
+	bits := uint(len(parts) * 6)
+	if num >= 1<<bits {
+		return "", errors.New("version too large " + version)
+	}
+
+	encoded := make([]byte, len(parts))
+	for i := range encoded {
+		encoded[len(encoded)-1-i] = analyticsChars[num&63]
+		num >>= 6
+	}
+	return string(encoded), nil
+}
@@ -0,0 +1,67 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import (
+	"crypto/subtle"
+	"errors"
+	"strconv"
+)
+
+// ErrInvalidSignature is returned when the signature of an upload
+// response doesn't match its public ID and version.
+var ErrInvalidSignature = errors.New("invalid response signature")
+
+// WithStrictSignatures fails the uploads with ErrInvalidSignature, along
+// with their response, when the signature of the response is missing or
+// doesn't match.
+// Otherwise the mismatch is only told by Response.Verified.
+func WithStrictSignatures() Option {
+	return func(s *Service) error {
+		s.strictSignatures = true
+		return nil
+	}
+}
+
+// VerifyResponse checks the signature of an upload response, e.g. of the
+// result of a direct upload handed back by a browser
+func (s *Service) VerifyResponse(resp *Response) error {
+	if s.apiSecret == "" {
+		return errors.New("no API secret to verify with")
+	}
+	if resp == nil || resp.Signature == "" {
+		return ErrInvalidSignature
+	}
+
+	expected := sign(map[string]string{
+		"public_id": resp.PublicID,
+		"version":   strconv.FormatUint(uint64(resp.Version), 10),
+	}, s.apiSecret)
+	if subtle.ConstantTimeCompare([]byte(expected), []byte(resp.Signature)) != 1 {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// verified wraps an upload to verify its response. A response without a
+// signature is not verified, and fails in strict mode, but the ones of the
+// duplicates skipped by Dedupe, made up by the service, and the ones of the
+// async uploads, which only hold a batch ID.
+func (s *Service) verified(upload func() (*Response, error)) func() (*Response, error) {
+	return func() (*Response, error) {
+		resp, err := upload()
+		if err != nil || resp == nil || resp.Duplicate || (resp.BatchID != "" && resp.PublicID == "") {
+			return resp, err
+		}
+
+		resp.Verified = s.VerifyResponse(resp) == nil
+		if !resp.Verified && s.strictSignatures {
+			return resp, ErrInvalidSignature
+		}
+		return resp, nil
+	}
+}
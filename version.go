@@ -0,0 +1,111 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import (
+	"context"
+	"strconv"
+	"strings"
+)
+
+// The version of an asset changes on every upload of its public ID. The
+// delivery URLs embed the latest version uploaded through the service so
+// re-uploads are not hidden by the CDN cached copies, see URLOptions.
+
+// ExtractVersion returns the version component of a delivery URL or path,
+// e.g. 1584624255 of .../upload/v1584624255/sample.jpg, zero when absent
+func ExtractVersion(u string) uint {
+	if i := strings.IndexAny(u, "?#"); i >= 0 {
+		u = u[:i]
+	}
+	for _, c := range strings.Split(u, "/") {
+		if len(c) < 2 || c[0] != 'v' {
+			continue
+		}
+		if v, err := strconv.ParseUint(c[1:], 10, 64); err == nil && v > 0 {
+			return uint(v)
+		}
+	}
+	return 0
+}
+
+// CompareVersions returns -1, 0 or 1 as version a is older than, the same
+// as or newer than b, the versions being the upload timestamps
+func CompareVersions(a, b uint) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// LatestVersion returns the current version of an asset from the Admin API,
+// the URLs built afterwards embed it
+func (s *Service) LatestVersion(ctx context.Context, publicID string) (uint, error) {
+	id := s.prefixed(publicID)
+	res, err := s.findResource(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+	s.storeVersion(res.ResourceType, res.Type, id, res.Version)
+	return res.Version, nil
+}
+
+// rememberVersion wraps an upload to remember the version of its asset
+func (s *Service) rememberVersion(opts *UploadOptions, upload func() (*Response, error)) func() (*Response, error) {
+	o := s.uploadOptions(opts)
+	var typ string
+	if o != nil {
+		typ = o.Type
+	}
+	return func() (*Response, error) {
+		resp, err := upload()
+		if err == nil && resp != nil && resp.PublicID != "" {
+			rt := resp.ResourceType
+			if rt == "" {
+				rt = o.resourceType()
+			}
+			s.storeVersion(rt, typ, resp.PublicID, resp.Version)
+		}
+		return resp, err
+	}
+}
+
+// storeVersion remembers version unless a newer one is known already,
+// e.g. of a concurrent upload
+func (s *Service) storeVersion(rt, typ, publicID string, version uint) {
+	if version == 0 {
+		return
+	}
+	key := versionKey(rt, typ, publicID)
+	s.state.mu.Lock()
+	defer s.state.mu.Unlock()
+	if known, ok := s.state.versions.Load(key); !ok || CompareVersions(known.(uint), version) < 0 {
+		s.state.versions.Store(key, version)
+	}
+}
+
+// knownVersion returns the remembered version of an asset, zero if unknown
+func (s *Service) knownVersion(rt, typ, publicID string) uint {
+	if v, ok := s.state.versions.Load(versionKey(rt, typ, publicID)); ok {
+		return v.(uint)
+	}
+	return 0
+}
+
+func (s *Service) forgetVersion(rt, typ, publicID string) {
+	s.state.versions.Delete(versionKey(rt, typ, publicID))
+}
+
+func versionKey(rt, typ, publicID string) string {
+	if typ == "" {
+		typ = "upload"
+	}
+	return rt + "/" + typ + "/" + publicID
+}
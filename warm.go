@@ -0,0 +1,75 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import (
+	"context"
+	"sync"
+)
+
+// Explicit calls sent at once by WarmDerived
+const warmConcurrency = 4
+
+// WarmResult is the outcome of warming the derived versions of an asset.
+type WarmResult struct {
+	PublicID string
+	Response *Response
+	Err      error
+}
+
+// WarmDerived generates the derived versions of the image assets ahead of
+// the traffic with explicit calls, made concurrently through the limiter of
+// the Upload API, see WithRateLimit. It doesn't back off: once the API
+// answers with a rate limit, the allowance is spent until it resets, so the
+// assets not tried yet are given up on and get the rate limit error. They
+// are to be warmed again after the reset, IsRetryable tells them apart.
+// The results are in the order of publicIDs.
+func (s *Service) WarmDerived(ctx context.Context, publicIDs []string, transformations []Transformation) []WarmResult {
+	eager := make([]string, len(transformations))
+	for i, t := range transformations {
+		eager[i] = t.Name
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		limitErr error
+	)
+	results := make([]WarmResult, len(publicIDs))
+	sem := make(chan struct{}, warmConcurrency)
+	for i, id := range publicIDs {
+		results[i].PublicID = id
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(res *WarmResult) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			mu.Lock()
+			err := limitErr
+			mu.Unlock()
+			if err != nil {
+				res.Err = err
+				return
+			}
+
+			res.Response, res.Err = s.Explicit(ctx, res.PublicID, &UploadOptions{Eager: eager})
+			if Category(res.Err) == CategoryRateLimit {
+				mu.Lock()
+				if limitErr == nil {
+					limitErr = res.Err
+				}
+				mu.Unlock()
+			}
+		}(&results[i])
+	}
+	wg.Wait()
+
+	return results
+}
@@ -0,0 +1,70 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// warmupTimeout bounds the time Dial spends opening the connections
+const warmupTimeout = 5 * time.Second
+
+// WithWarmup opens n connections to the API host on Dial, so the first
+// requests don't wait for the TCP and TLS handshakes. Dial waits for them
+// up to warmupTimeout, a failed warmup doesn't fail Dial. One connection
+// is enough over HTTP/2. They are kept open for the IdleConnTimeout of
+// the transport, see TransportConfig.
+func WithWarmup(n int) Option {
+	return func(s *Service) error {
+		s.warmup = n
+		return nil
+	}
+}
+
+// Warmup opens n connections to the API host, returning the first error
+func (s *Service) Warmup(ctx context.Context, n int) error {
+	errs := make(chan error, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- s.warm(ctx)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// warm sends a bodyless request to the API host, any status will do
+func (s *Service) warm(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, s.apiURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", s.userAgent)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	// Drained for the connection to be reused
+	io.Copy(ioutil.Discard, resp.Body)
+	return resp.Body.Close()
+}
@@ -0,0 +1,68 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+)
+
+// WidgetSignature is the signed configuration handed to a browser widget,
+// it is JSON encoded with the names the widgets use.
+type WidgetSignature struct {
+	CloudName string `json:"cloud_name"`
+	APIKey    string `json:"api_key"`
+	Username  string `json:"username,omitempty"` // Media Library widget only
+	Timestamp int64  `json:"timestamp"`
+	Signature string `json:"signature"`
+}
+
+// UploadWidgetSignature signs the parameters of a signed Upload Widget,
+// i.e. the params_to_sign given to its uploadSignature callback.
+// A timestamp is added unless the widget sent one.
+func (s *Service) UploadWidgetSignature(paramsToSign map[string]string) (*WidgetSignature, error) {
+	params := make(map[string]string, len(paramsToSign)+1)
+	for key, value := range paramsToSign {
+		params[key] = value
+	}
+
+	timestamp := s.signingTime().Unix()
+	if t, ok := params["timestamp"]; ok {
+		var err error
+		if timestamp, err = strconv.ParseInt(t, 10, 64); err != nil {
+			return nil, err
+		}
+	}
+	params["timestamp"] = strconv.FormatInt(timestamp, 10)
+
+	return &WidgetSignature{
+		CloudName: s.cloudName,
+		APIKey:    s.apiKey,
+		Timestamp: timestamp,
+		Signature: sign(params, s.apiSecret),
+	}, nil
+}
+
+// MediaLibraryWidgetSignature signs the login of username to the
+// Media Library widget
+func (s *Service) MediaLibraryWidgetSignature(username string) *WidgetSignature {
+	timestamp := s.signingTime().Unix()
+
+	// Unlike the API, the widget expects a SHA-256 of the unsorted query
+	sum := sha256.Sum256([]byte("cloud_name=" + s.cloudName +
+		"&timestamp=" + strconv.FormatInt(timestamp, 10) +
+		"&username=" + username + s.apiSecret))
+
+	return &WidgetSignature{
+		CloudName: s.cloudName,
+		APIKey:    s.apiKey,
+		Username:  username,
+		Timestamp: timestamp,
+		Signature: hex.EncodeToString(sum[:]),
+	}
+}
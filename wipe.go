@@ -0,0 +1,88 @@
+// Copyright 2013 Mathias Monnerville and Anthony Baillard.
+// Modified 2020 Simon Partridge & Benjamin King & Habib Rosyad
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cloudinary
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+)
+
+// ErrNotConfirmed is returned by DeleteAllResources unless confirmed.
+var ErrNotConfirmed = errors.New("deletion of all resources not confirmed")
+
+// DeleteAllOptions guard and shape DeleteAllResources.
+type DeleteAllOptions struct {
+	// Confirm must be the cloud name of the service, for a wipe not to
+	// be run against the wrong cloud by mistake
+	Confirm      string
+	Type         string // Delivery type, upload by default
+	KeepOriginal bool   // Delete the derived resources only
+	// All keeps deleting until no resource is left, otherwise the result
+	// is partial past 1000 resources, as is the one of a dry run
+	All bool
+}
+
+// DeleteAllResources deletes every resource of a type, e.g. to tear down a
+// test cloud. It is refused on a service with a folder prefix, see
+// DeleteResourcesByPrefix instead.
+func (s *Service) DeleteAllResources(ctx context.Context, resourceType string, opts *DeleteAllOptions) (*DeleteResult, error) {
+	// Not confirmed by an empty cloud name
+	if opts == nil || opts.Confirm == "" || opts.Confirm != s.cloudName {
+		return nil, ErrNotConfirmed
+	}
+	if s.folderPrefix != "" {
+		return nil, errors.New("refusing to delete all resources beyond the folder prefix")
+	}
+
+	typ := opts.Type
+	if typ == "" {
+		typ = "upload"
+	}
+	query := url.Values{"delete_all": {"true"}}
+	if opts.KeepOriginal {
+		query.Set("keep_original", "true")
+	}
+
+	res := &DeleteResult{Deleted: map[string]string{}, KeepOriginal: opts.KeepOriginal}
+	for {
+		r, err := s.newAdminRequest(http.MethodDelete, "resources/"+resourceType+"/"+typ, query, nil)
+		if err != nil {
+			return nil, err
+		}
+		r.ctx = ctx
+		r.mutates = true
+
+		if s.skip(r) {
+			limit := maxDeletedPerCall
+			if opts.All {
+				limit = 0
+			}
+			res, err := s.dryRunDeleted(ctx, resourceType, ResourcesQuery{Type: typ}, limit)
+			if res != nil {
+				res.KeepOriginal = opts.KeepOriginal
+			}
+			return res, err
+		}
+
+		page := &DeleteResult{}
+		if err := s.call(r, page); err != nil {
+			return res, err
+		}
+		for id, status := range page.Deleted {
+			res.Deleted[id] = status
+		}
+		res.Partial, res.NextCursor = page.Partial, page.NextCursor
+		if !opts.All || !page.Partial {
+			return res, nil
+		}
+		if page.NextCursor != "" {
+			query.Set("next_cursor", page.NextCursor)
+		}
+	}
+}